@@ -0,0 +1,72 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"runtime"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+)
+
+// Analyze checks the type-checked files of pkg with the config, and returns the findings
+// sorted by position. It is for tools that load packages themselves, eg with go/packages,
+// rather than running the analyzer with a driver.
+//
+// The config is not changed: Analyze uses a clone of it, whose ReportFunc collects the
+// findings. Suggested fixes are dropped, like with ReportFunc. Unlike with a driver, facts
+// about other packages are not available, so calls to their functions that never return,
+// like log.Fatal, are only known from NoReturnFuncsSlice.
+func Analyze(config *Config, fset *token.FileSet, pkg *types.Package, info *types.Info, files []*ast.File) ([]Finding, error) {
+	var findings []Finding
+	c := config.Clone()
+	c.ReportFunc = func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	pass := &analysis.Pass{
+		Fset:              fset,
+		Files:             files,
+		Pkg:               pkg,
+		TypesInfo:         info,
+		TypesSizes:        types.SizesFor("gc", runtime.GOARCH),
+		ResultOf:          make(map[*analysis.Analyzer]interface{}),
+		ReadFile:          os.ReadFile,
+		ImportObjectFact:  func(types.Object, analysis.Fact) bool { return false },
+		ExportObjectFact:  func(types.Object, analysis.Fact) {},
+		ImportPackageFact: func(*types.Package, analysis.Fact) bool { return false },
+		ExportPackageFact: func(analysis.Fact) {},
+		Report: func(d analysis.Diagnostic) {
+			findings = append(findings, Finding{
+				Check:      d.Category,
+				Pos:        d.Pos,
+				End:        d.End,
+				Position:   fset.Position(d.Pos),
+				Message:    d.Message,
+				Confidence: ConfidenceHigh,
+			})
+		},
+	}
+
+	// Run the analyzers the spancheck analyzer requires, in order.
+	for _, a := range []*analysis.Analyzer{inspect.Analyzer, ctrlflow.Analyzer} {
+		pass.Analyzer = a
+		result, err := a.Run(pass)
+		if err != nil {
+			return nil, err
+		}
+		pass.ResultOf[a] = result
+	}
+
+	pass.Analyzer = newAnalyzer(c)
+	if _, err := pass.Analyzer.Run(pass); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Pos < findings[j].Pos })
+	return findings, nil
+}
@@ -0,0 +1,110 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+const (
+	// contextWithSpan is the function that attaches an OpenTelemetry span to a context.
+	contextWithSpan = "go.opentelemetry.io/otel/trace.ContextWithSpan"
+
+	// spanFromContext is the function that gets the OpenTelemetry span of a context,
+	// which is ended by whoever started it.
+	spanFromContext = "go.opentelemetry.io/otel/trace.SpanFromContext"
+)
+
+// getAttachedSpans returns the spans attached to a context with trace.ContextWithSpan(ctx, span)
+// that are not started in node with a start span function, but created by another call in it,
+// eg `span := newSpan()`. Spans from trace.SpanFromContext, parameters and variables declared
+// outside of funcScope are not returned, since they are ended elsewhere.
+func getAttachedSpans(info *types.Info, node ast.Node, funcScope *types.Scope, spanVars map[*ast.Ident]spanVar) map[*ast.Ident]spanVar {
+	tracked := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		tracked[sv.vr] = true
+	}
+
+	attached := make(map[*types.Var]bool)
+	inspectFunc(node, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 || !isCallTo(info, call, contextWithSpan) {
+			return
+		}
+
+		if id, ok := ast.Unparen(call.Args[1]).(*ast.Ident); ok {
+			if v, ok := info.Uses[id].(*types.Var); ok && !tracked[v] && funcScope.Contains(v.Pos()) {
+				attached[v] = true
+			}
+		}
+	})
+	if len(attached) == 0 {
+		return nil
+	}
+
+	spans := make(map[*ast.Ident]spanVar)
+	inspectFunc(node, func(n ast.Node) {
+		var lhs []*ast.Ident
+		var rhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, e := range n.Lhs {
+				id, _ := e.(*ast.Ident)
+				lhs = append(lhs, id)
+			}
+			rhs = n.Rhs
+		case *ast.ValueSpec:
+			lhs, rhs = n.Names, n.Values
+		default:
+			return
+		}
+
+		for i, id := range lhs {
+			if id == nil {
+				continue
+			}
+			v, ok := info.Defs[id].(*types.Var)
+			if !ok || !attached[v] {
+				continue
+			}
+
+			// The span must be created by a call, eg `span := newSpan()`.
+			var value ast.Expr
+			if len(lhs) == len(rhs) {
+				value = rhs[i]
+			} else if len(rhs) == 1 {
+				value = rhs[0]
+			}
+			call, ok := ast.Unparen(value).(*ast.CallExpr)
+			if !ok || isCallTo(info, call, spanFromContext) {
+				continue
+			}
+
+			spans[id] = spanVar{
+				stmt:     n,
+				id:       id,
+				vr:       v,
+				spanType: spanOpenTelemetry,
+			}
+		}
+	})
+	return spans
+}
+
+// isCallTo reports whether call calls the function with the full name fullName.
+func isCallTo(info *types.Info, call *ast.CallExpr, fullName string) bool {
+	fn, ok := calleeObject(info, call).(*types.Func)
+	return ok && fn.FullName() == fullName
+}
+
+// inspectFunc calls f for each node in the function node, but not in nested functions.
+func inspectFunc(node ast.Node, f func(ast.Node)) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok && n != node {
+			return false
+		}
+		if n != nil {
+			f(n)
+		}
+		return true
+	})
+}
@@ -0,0 +1,87 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"slices"
+	"strings"
+)
+
+// maxBuildTags caps the tags of a build constraint that requiresBuildTag tries every
+// assignment of. Constraints with more tags are assumed to not require any.
+const maxBuildTags = 16
+
+// getIgnoredFiles returns the files whose //go:build constraint requires one of tags, eg
+// "debug" for `//go:build debug && !race`, or "!production" for `//go:build !production`.
+// A tag on one side of an `||` is not required, so `//go:build debug || linux` is not ignored.
+func getIgnoredFiles(files []*ast.File, tags []string) map[*ast.File]bool {
+	ignored := make(map[*ast.File]bool)
+	if len(tags) == 0 {
+		return ignored
+	}
+
+	for _, file := range files {
+		expr := getBuildConstraint(file)
+		if expr == nil {
+			continue
+		}
+		for _, tag := range tags {
+			if requiresBuildTag(expr, tag) {
+				ignored[file] = true
+				break
+			}
+		}
+	}
+	return ignored
+}
+
+// getBuildConstraint returns the file's //go:build constraint, or nil if it has none.
+func getBuildConstraint(file *ast.File) constraint.Expr {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break // constraints must come before the package clause
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			if expr, err := constraint.Parse(c.Text); err == nil {
+				return expr
+			}
+		}
+	}
+	return nil
+}
+
+// requiresBuildTag reports whether expr can only be satisfied with tag set, or, if tag has a
+// "!" prefix, only with it unset. It tries every assignment of the other tags in expr.
+func requiresBuildTag(expr constraint.Expr, tag string) bool {
+	name, negated := strings.CutPrefix(tag, "!")
+
+	var others []string
+	seen := map[string]bool{name: true}
+	expr.Eval(func(t string) bool {
+		if !seen[t] {
+			seen[t] = true
+			others = append(others, t)
+		}
+		return false
+	})
+	if len(others) > maxBuildTags {
+		return false
+	}
+
+	for bits := 0; bits < 1<<len(others); bits++ {
+		ok := expr.Eval(func(t string) bool {
+			if t == name {
+				return negated // the assignment that does not meet the tag
+			}
+			i := slices.Index(others, t)
+			return bits&(1<<i) != 0
+		})
+		if ok {
+			return false
+		}
+	}
+	return true
+}
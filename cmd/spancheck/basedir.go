@@ -0,0 +1,55 @@
+package main
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// baseDirFlag makes the binary print file paths relative to a directory, so the output does
+// not depend on where the code is checked out.
+const baseDirFlag = "base-dir"
+
+// getFlagValue returns the value of the flag name in args, set as `-name value` or
+// `-name=value`, and whether it is set.
+func getFlagValue(args []string, name string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		flagName, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || flagName != name {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		return value, true
+	}
+	return "", false
+}
+
+// baseDir is an absolute directory that file paths are printed relative to. Paths are only
+// made relative when printed, so the analysis and the lines of context printed with -c use
+// the real files. An empty baseDir prints paths as they are.
+type baseDir string
+
+// rel returns filename relative to d if it is in d, and filename otherwise.
+func (d baseDir) rel(filename string) string {
+	if d == "" {
+		return filename
+	}
+
+	rel, err := filepath.Rel(string(d), filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filename
+	}
+	return rel
+}
+
+// posn formats pos like token.Position.String, with its file name relative to d.
+func (d baseDir) posn(pos token.Position) string {
+	pos.Filename = d.rel(pos.Filename)
+	return pos.String()
+}
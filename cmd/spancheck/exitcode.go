@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jjti/go-spancheck"
+)
+
+// exitCodeFlag makes the binary exit nonzero when there are findings, even with -json.
+const exitCodeFlag = "exit-code"
+
+// hasBoolFlag reports whether args set the boolean flag name.
+func hasBoolFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		flagName, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || flagName != name {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		return !hasValue || (err == nil && enabled)
+	}
+	return false
+}
+
+// unsupportedFlags are singlechecker's flags that runWithExitCode does not implement, with
+// whether they are boolean. They are registered so setting them is an error, rather than
+// being parsed as another flag's value or silently ignored.
+var unsupportedFlags = map[string]bool{
+	"fix":        true,
+	"flags":      true,
+	"debug":      false,
+	"cpuprofile": false,
+	"memprofile": false,
+	"trace":      false,
+}
+
+// runWithExitCode analyzes the packages in args with a, printing findings like singlechecker,
+// or grouped by function with the group-by-func flag, with paths relative to the base-dir
+// flag if set. It returns 3 if there are findings and either the exit-code flag is set or the
+// output is text, 1 if analysis failed or args set a flag it does not support, and 0
+// otherwise. The exit-code, group-by-func and base-dir flags must already be registered on
+// the command line flag set, which is parsed with args.
+func runWithExitCode(a *analysis.Analyzer, config *spancheck.Config, exitCode, groupFindings *bool, baseDirPath *string, args []string) int {
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	// The defaults match singlechecker's.
+	jsonOutput := flag.Bool("json", false, "emit JSON output")
+	tests := flag.Bool("test", true, "indicates whether test files should be analyzed, too")
+	contextLines := flag.Int("c", -1, "display offending line with this many lines of context")
+	usage := fmt.Sprintf("not supported with -%s, -%s or -%s", exitCodeFlag, groupByFuncFlag, baseDirFlag)
+	for name, isBool := range unsupportedFlags {
+		if isBool {
+			flag.Bool(name, false, usage)
+		} else {
+			flag.String(name, "", usage)
+		}
+	}
+	_ = flag.CommandLine.Parse(args) // exits on error
+
+	var unsupported []string
+	baseDirSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if _, ok := unsupportedFlags[f.Name]; ok {
+			unsupported = append(unsupported, "-"+f.Name)
+		}
+		baseDirSet = baseDirSet || f.Name == baseDirFlag
+	})
+	if len(unsupported) > 0 {
+		log.Printf("%s %s", strings.Join(unsupported, ", "), usage)
+		return 1
+	}
+
+	var base baseDir
+	if baseDirSet {
+		if *baseDirPath == "" {
+			log.Printf("-%s must not be empty", baseDirFlag)
+			return 1
+		}
+		abs, err := filepath.Abs(*baseDirPath)
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		base = baseDir(abs)
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule, // like singlechecker, which sets Pass.Module
+		Tests: *tests,
+	}, flag.Args()...)
+	if err == nil && len(pkgs) == 0 {
+		err = fmt.Errorf("%s matched no packages", strings.Join(flag.Args(), " "))
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	code := 0
+	if packages.PrintErrors(pkgs) > 0 {
+		code = 1
+	}
+
+	// Grouped findings are collected with their confidence, which diagnostics do not have.
+	var (
+		mu       sync.Mutex
+		findings []spancheck.Finding
+	)
+	if *groupFindings {
+		config.ReportFunc = func(f spancheck.Finding) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			findings = append(findings, f)
+		}
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	switch {
+	case *groupFindings && *jsonOutput:
+		err = printGroupedJSON(os.Stdout, groupByFunc(pkgs, findings, base))
+	case *groupFindings:
+		err = printGroupedText(os.Stderr, groupByFunc(pkgs, findings, base))
+	case *jsonOutput:
+		err = printJSON(os.Stdout, graph.Roots, base)
+	default:
+		err = printText(os.Stderr, graph.Roots, *contextLines, base)
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			code = 1
+		} else if (len(act.Diagnostics) > 0 || len(findings) > 0) && (*exitCode || !*jsonOutput) {
+			return 3
+		}
+	}
+	return code
+}
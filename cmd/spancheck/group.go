@@ -0,0 +1,184 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"slices"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/jjti/go-spancheck"
+)
+
+// groupByFuncFlag makes the binary print findings grouped by the function enclosing them.
+const groupByFuncFlag = "group-by-func"
+
+// funcFindings are the findings in one function.
+type funcFindings struct {
+	Package     string           `json:"package"`
+	Func        string           `json:"func,omitempty"` // empty for findings outside functions
+	Posn        string           `json:"posn,omitempty"`
+	Count       int              `json:"count"`
+	Diagnostics []funcDiagnostic `json:"diagnostics"`
+
+	pos token.Position
+}
+
+// funcDiagnostic is a finding in a function.
+type funcDiagnostic struct {
+	Category   string `json:"category,omitempty"`
+	Posn       string `json:"posn"`
+	Message    string `json:"message"`
+	Confidence string `json:"confidence,omitempty"`
+}
+
+// groupByFunc groups the findings in pkgs by the function declaration enclosing them, with
+// file paths relative to base. Functions with the most findings come first.
+func groupByFunc(pkgs []*packages.Package, findings []spancheck.Finding, base baseDir) []*funcFindings {
+	slices.SortStableFunc(findings, func(a, b spancheck.Finding) int {
+		return cmp.Or(cmp.Compare(a.Position.Filename, b.Position.Filename), cmp.Compare(a.Position.Offset, b.Position.Offset))
+	})
+
+	var groups []*funcFindings
+	byFunc := make(map[string]*funcFindings)
+	seen := make(map[funcDiagnostic]bool) // test variants of a package report the same findings
+	for _, f := range findings {
+		diag := funcDiagnostic{
+			Category:   f.Check,
+			Posn:       base.posn(f.Position),
+			Message:    f.Message,
+			Confidence: f.Confidence,
+		}
+		if seen[diag] {
+			continue
+		}
+		seen[diag] = true
+
+		pkg := enclosingPackage(pkgs, f.Pos)
+		if pkg == nil {
+			continue
+		}
+
+		// Findings outside functions are grouped by package.
+		decl := enclosingFunc(pkg, f.Pos)
+		key := pkg.PkgPath
+		if decl != nil {
+			key = pkg.Fset.Position(decl.Pos()).String()
+		}
+
+		g, ok := byFunc[key]
+		if !ok {
+			g = &funcFindings{Package: pkg.PkgPath}
+			if decl != nil {
+				g.Func = funcName(decl)
+				g.pos = pkg.Fset.Position(decl.Pos())
+				g.Posn = base.posn(g.pos)
+			}
+			byFunc[key] = g
+			groups = append(groups, g)
+		}
+
+		g.Count++
+		g.Diagnostics = append(g.Diagnostics, diag)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		if groups[i].pos.Filename != groups[j].pos.Filename {
+			return groups[i].pos.Filename < groups[j].pos.Filename
+		}
+		return groups[i].pos.Offset < groups[j].pos.Offset
+	})
+	return groups
+}
+
+// enclosingPackage returns the package of pkgs with a file enclosing pos, or nil.
+func enclosingPackage(pkgs []*packages.Package, pos token.Pos) *packages.Package {
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			if file.Pos() <= pos && pos <= file.End() {
+				return pkg
+			}
+		}
+	}
+	return nil
+}
+
+// enclosingFunc returns the function declaration in pkg enclosing pos, or nil.
+func enclosingFunc(pkg *packages.Package, pos token.Pos) *ast.FuncDecl {
+	for _, file := range pkg.Syntax {
+		if pos < file.Pos() || pos > file.End() {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Pos() <= pos && pos <= fn.End() {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// funcName returns the name of the function declaration, with its receiver type for
+// methods, eg T.Method or (*T).Method.
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	recv, ptr := decl.Recv.List[0].Type, false
+	if x, ok := recv.(*ast.StarExpr); ok {
+		recv, ptr = x.X, true
+	}
+	switch x := recv.(type) {
+	case *ast.IndexExpr: // generic receiver, eg T[K]
+		recv = x.X
+	case *ast.IndexListExpr:
+		recv = x.X
+	}
+
+	if ptr {
+		return fmt.Sprintf("(*%s).%s", types.ExprString(recv), decl.Name.Name)
+	}
+	return fmt.Sprintf("%s.%s", types.ExprString(recv), decl.Name.Name)
+}
+
+// printGroupedJSON prints the groups as a JSON array.
+func printGroupedJSON(w io.Writer, groups []*funcFindings) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if groups == nil {
+		groups = []*funcFindings{}
+	}
+	return enc.Encode(groups)
+}
+
+// printGroupedText prints each group's function and count, followed by its findings.
+func printGroupedText(w io.Writer, groups []*funcFindings) error {
+	for _, g := range groups {
+		var err error
+		if g.Func == "" {
+			_, err = fmt.Fprintf(w, "%s: %d span issues outside functions\n", g.Package, g.Count)
+		} else {
+			_, err = fmt.Fprintf(w, "%s: %s has %d span issues\n", g.Posn, g.Func, g.Count)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, d := range g.Diagnostics {
+			if _, err := fmt.Fprintf(w, "\t%s: %s\n", d.Posn, d.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
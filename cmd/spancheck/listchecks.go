@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jjti/go-spancheck"
+)
+
+// listChecksFlag makes the binary print the available checks and exit.
+const listChecksFlag = "list-checks"
+
+// listChecks prints the checks, sorted by name, one per line with tab-separated columns: its
+// name, "enabled" or "disabled" by config after parsing the analyzer's flags in args, and its
+// description.
+func listChecks(w io.Writer, a *analysis.Analyzer, config *spancheck.Config, args []string) int {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	fs.Bool(listChecksFlag, false, "print the available checks, whether they are enabled and their descriptions, then exit")
+	_ = fs.Parse(args) // exits on error
+
+	names := make([]string, 0, len(spancheck.Checks))
+	for name := range spancheck.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		check := spancheck.Checks[name]
+		state := "disabled"
+		if config.CheckEnabled(check) {
+			state = "enabled"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", name, state, check.Description()); err != nil {
+			return 1
+		}
+	}
+	return 0
+}
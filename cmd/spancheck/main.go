@@ -2,8 +2,7 @@ package main
 
 import (
 	"flag"
-	"fmt"
-	"strings"
+	"os"
 
 	"golang.org/x/tools/go/analysis/singlechecker"
 
@@ -11,31 +10,23 @@ import (
 )
 
 func main() {
-	// Set the list of checks to enable.
-	checkOptions := []string{}
-	for check := range spancheck.Checks {
-		checkOptions = append(checkOptions, check)
-	}
-
-	checkStrings := ""
-	flag.StringVar(&checkStrings, "checks", "end", fmt.Sprintf("comma-separated list of checks to enable (options: %v)", strings.Join(checkOptions, ", ")))
-
-	// Set the list of function signatures to ignore checks for.
-	ignoreCheckSignatures := ""
-	flag.StringVar(&ignoreCheckSignatures, "ignore-check-signatures", "", "comma-separated list of regex for function signatures that disable checks on errors")
+	config := spancheck.NewDefaultConfig()
+	a := spancheck.NewAnalyzerWithConfig(config)
 
-	extraStartSpanSignatures := ""
-	flag.StringVar(&extraStartSpanSignatures, "extra-start-span-signatures", "", "comma-separated list of regex:telemetry-type for function signatures that indicate the start of a span")
-
-	flag.Parse()
-
-	cfg := spancheck.NewDefaultConfig()
-	cfg.EnabledChecks = strings.Split(checkStrings, ",")
-	cfg.IgnoreChecksSignaturesSlice = strings.Split(ignoreCheckSignatures, ",")
+	flag.Bool(listChecksFlag, false, "print the available checks, whether they are enabled and their descriptions, then exit")
+	if hasBoolFlag(os.Args[1:], listChecksFlag) {
+		os.Exit(listChecks(os.Stdout, a, config, os.Args[1:]))
+	}
 
-	if extraStartSpanSignatures != "" {
-		cfg.StartSpanMatchersSlice = append(cfg.StartSpanMatchersSlice, strings.Split(extraStartSpanSignatures, ",")...)
+	// singlechecker always exits zero with -json, cannot group findings, and prints absolute
+	// paths, so those are handled here instead.
+	baseDirPath := flag.String(baseDirFlag, "", "print file paths relative to this directory, eg the module root, instead of absolute paths")
+	exitCode := flag.Bool(exitCodeFlag, false, "exit with code 3 when there are findings, even with -json")
+	groupFindings := flag.Bool(groupByFuncFlag, false, "print findings grouped by the function enclosing them, with the functions with most findings first")
+	_, hasBaseDir := getFlagValue(os.Args[1:], baseDirFlag)
+	if hasBaseDir || hasBoolFlag(os.Args[1:], exitCodeFlag) || hasBoolFlag(os.Args[1:], groupByFuncFlag) {
+		os.Exit(runWithExitCode(a, config, exitCode, groupFindings, baseDirPath, os.Args[1:]))
 	}
 
-	singlechecker.Main(spancheck.NewAnalyzerWithConfig(cfg))
+	singlechecker.Main(a)
 }
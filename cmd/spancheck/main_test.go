@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jjti/go-spancheck"
+)
+
+// bin is the path of the spancheck binary, built once by TestMain for the tests to run.
+var bin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "spancheck")
+	if err != nil {
+		log.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	bin = filepath.Join(dir, "spancheck")
+
+	code := 1
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		log.Printf("Unexpected error building binary: %v\n%s", err, out)
+	} else {
+		code = m.Run()
+	}
+
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func Test_flags(t *testing.T) {
+	t.Parallel()
+
+	out, err := exec.Command(bin, "-flags").Output()
+	if err != nil {
+		t.Fatalf("Unexpected error printing flags: %v", err)
+	}
+
+	var flags []struct {
+		Name string
+	}
+	if err := json.Unmarshal(out, &flags); err != nil {
+		t.Fatalf("Unexpected error parsing flags: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, f := range flags {
+		names[f.Name] = true
+	}
+
+	for _, want := range []string{
+		"checks",
+		"ignore-check-signatures",
+		"extra-start-span-signatures",
+		"no-return-funcs",
+		"end-funcs",
+		"deferred-end-funcs",
+		"entry-points",
+		"cheap-attribute-funcs",
+		"exclude-pkgs",
+		"ignore-build-tags",
+		"ignore-span-name-regex",
+		"strict-set-status-code",
+		"must-have-span-funcs",
+		"diff-file",
+		"tracer-name-template",
+		"panic-on-error-funcs",
+		"span-helper-pkgs",
+		"record-error-satisfies-set-status",
+		"same-func-end",
+		"skip-main-end",
+		"relax-test-files",
+		"all-paths",
+		"strict-record-error",
+		"strict-recovered-errors",
+		"max-func-nodes",
+		"defer-within",
+		"loop-span-depth",
+		"explain",
+		"exit-code",
+		"list-checks",
+		"base-dir",
+	} {
+		if !names[want] {
+			t.Errorf("Missing flag=%s, got=%v", want, names)
+		}
+	}
+}
+
+func Test_listChecks(t *testing.T) {
+	t.Parallel()
+
+	out, err := exec.Command(bin, "-list-checks", "-checks", "end,set-status").Output()
+	if err != nil {
+		t.Fatalf("Unexpected error listing checks: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != len(spancheck.Checks) {
+		t.Fatalf("Unexpected number of checks=%d, want=%d\n%s", len(lines), len(spancheck.Checks), out)
+	}
+
+	var names []string
+	for _, line := range lines {
+		cols := strings.Split(line, "\t")
+		if len(cols) != 3 || cols[2] == "" {
+			t.Fatalf("Unexpected line=%q, want name, state and description", line)
+		}
+		names = append(names, cols[0])
+
+		want := "disabled"
+		if cols[0] == "end" || cols[0] == "set-status" {
+			want = "enabled"
+		}
+		if cols[1] != want {
+			t.Errorf("Unexpected state=%s of check=%s, want=%s", cols[1], cols[0], want)
+		}
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("Unexpected order of checks=%v, want sorted", names)
+	}
+}
+
+func Test_exitCode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"-json", "."}, want: 0},
+		{args: []string{"-json", "-exit-code", "."}, want: 3},
+		{args: []string{"-exit-code", "."}, want: 3},
+		{args: []string{"-exit-code=false", "."}, want: 3},
+		{args: []string{"-json", "-group-by-func", "."}, want: 0},
+		{args: []string{"-json", "-group-by-func", "-exit-code", "."}, want: 3},
+		{args: []string{"-group-by-func", "."}, want: 3},
+		{args: []string{"-exit-code", "-fix", "."}, want: 1},
+		{args: []string{"-group-by-func", "-cpuprofile", "cpu.out", "."}, want: 1},
+	} {
+		cmd := exec.Command(bin, tc.args...)
+		cmd.Dir = filepath.Join("..", "..", "testdata", "base")
+		out, err := cmd.Output()
+
+		got := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			got = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("Unexpected error running %v: %v", tc.args, err)
+		}
+
+		if got != tc.want {
+			t.Errorf("Unexpected exit code=%d running %v, want=%d\n%s", got, tc.args, tc.want, out)
+		}
+		if got == 1 {
+			continue // no packages are analyzed
+		}
+		if slices.Contains(tc.args, "-json") && !json.Valid(out) {
+			t.Errorf("Unexpected invalid JSON running %v: %s", tc.args, out)
+		}
+		if slices.Contains(tc.args, "-json") && !bytes.Contains(out, []byte(`"category": "end"`)) {
+			t.Errorf("Missing category of diagnostics running %v: %s", tc.args, out)
+		}
+	}
+}
+
+func Test_baseDir(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"-base-dir", ".", "."}, want: []string{"\nbase.go:23:2: "}},
+		{args: []string{"-base-dir=../..", "."}, want: []string{filepath.Join("testdata", "base", "base.go") + ":23:2: "}},
+		{args: []string{"-base-dir", "../..", "-c", "1", "."}, want: []string{filepath.Join("testdata", "base", "base.go") + ":23:2: ", "\n23\t\totel.Tracer"}},
+		{args: []string{"-json", "-base-dir", "../..", "."}, want: []string{`"posn": "` + filepath.ToSlash(filepath.Join("testdata", "base", "base.go")) + ":23:2"}},
+		{args: []string{"-group-by-func", "-base-dir", ".", "."}, want: []string{"\tbase.go:23:2: "}},
+		{args: []string{"-group-by-func", "-json", "-base-dir", ".", "."}, want: []string{`"posn": "base.go:23:2"`}},
+	} {
+		cmd := exec.Command(bin, tc.args...)
+		cmd.Dir = filepath.Join("..", "..", "testdata", "base")
+		out, _ := cmd.CombinedOutput() // exits with code 3 for findings
+		out = append([]byte("\n"), out...)
+
+		for _, want := range tc.want {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("Missing %q running %v:%s", want, tc.args, out)
+			}
+		}
+		if abs, _ := filepath.Abs(cmd.Dir); strings.Contains(string(out), abs) {
+			t.Errorf("Unexpected absolute path=%s running %v:%s", abs, tc.args, out)
+		}
+	}
+}
+
+func Test_baseDirInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"-base-dir=", "."},
+		{"-base-dir", ".", "-fix", "."},
+	} {
+		cmd := exec.Command(bin, args...)
+		cmd.Dir = filepath.Join("..", "..", "testdata", "base")
+		out, err := cmd.CombinedOutput()
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Errorf("Unexpected error=%v running %v, want exit code 1:\n%s", err, args, out)
+		}
+	}
+}
+
+func Test_groupByFunc(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(bin, "-json", "-group-by-func", ".")
+	cmd.Dir = filepath.Join("..", "..", "testdata", "base")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Unexpected error running: %v", err)
+	}
+
+	var groups []struct {
+		Func        string
+		Count       int
+		Diagnostics []struct {
+			Posn    string
+			Message string
+		}
+	}
+	if err := json.Unmarshal(out, &groups); err != nil {
+		t.Fatalf("Unexpected error parsing output: %v\n%s", err, out)
+	}
+	if len(groups) == 0 {
+		t.Fatalf("Missing groups: %s", out)
+	}
+
+	for i, g := range groups {
+		if g.Func == "" {
+			t.Errorf("Missing func of group %d: %s", i, out)
+		}
+		if g.Count != len(g.Diagnostics) {
+			t.Errorf("Unexpected count=%d of %s, want=%d", g.Count, g.Func, len(g.Diagnostics))
+		}
+		if i > 0 && g.Count > groups[i-1].Count {
+			t.Errorf("Unexpected order, %s has more findings than %s", g.Func, groups[i-1].Func)
+		}
+	}
+}
+
+func Test_confidence(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(bin, "-json", "-group-by-func", ".")
+	cmd.Dir = filepath.Join("..", "..", "testdata", "checks", "confidence")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Unexpected error running: %v", err)
+	}
+
+	var groups []struct {
+		Diagnostics []struct {
+			Posn       string
+			Confidence string
+		}
+	}
+	if err := json.Unmarshal(out, &groups); err != nil {
+		t.Fatalf("Unexpected error parsing output: %v\n%s", err, out)
+	}
+	counts := make(map[string]int)
+	for _, g := range groups {
+		for _, d := range g.Diagnostics {
+			counts[d.Confidence]++
+		}
+	}
+	if counts["high"] != 4 || counts["medium"] != 4 || counts["low"] != 2 {
+		t.Errorf("Unexpected confidences=%v of grouped findings:\n%s", counts, out)
+	}
+}
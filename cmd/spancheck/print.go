@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// jsonDiagnostic is a diagnostic in the JSON output, in the format of singlechecker's.
+type jsonDiagnostic struct {
+	Category       string             `json:"category,omitempty"`
+	Posn           string             `json:"posn"`
+	Message        string             `json:"message"`
+	SuggestedFixes []jsonSuggestedFix `json:"suggested_fixes,omitempty"`
+	Related        []jsonRelated      `json:"related,omitempty"`
+}
+
+// jsonSuggestedFix is a suggested fix of a jsonDiagnostic.
+type jsonSuggestedFix struct {
+	Message string         `json:"message"`
+	Edits   []jsonTextEdit `json:"edits"`
+}
+
+// jsonTextEdit is an edit of a jsonSuggestedFix, with zero-based byte offsets in the file.
+type jsonTextEdit struct {
+	Filename string `json:"filename"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	New      string `json:"new"`
+}
+
+// jsonRelated is related information of a jsonDiagnostic.
+type jsonRelated struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+// forEachAction calls f for each action in roots and their dependencies, once.
+func forEachAction(roots []*checker.Action, f func(act *checker.Action)) {
+	seen := make(map[*checker.Action]bool)
+	var visit func(acts []*checker.Action)
+	visit = func(acts []*checker.Action) {
+		for _, act := range acts {
+			if !seen[act] {
+				seen[act] = true
+				f(act)
+				visit(act.Deps)
+			}
+		}
+	}
+	visit(roots)
+}
+
+// printText prints the diagnostics of roots, and the errors of analyses, like singlechecker,
+// with contextLines lines of context around each diagnostic if it is not negative. File
+// paths are made relative to base.
+func printText(w io.Writer, roots []*checker.Action, contextLines int, base baseDir) error {
+	// Test variants of a package report the same diagnostics.
+	type key struct {
+		pos, end token.Position
+		message  string
+	}
+	seen := make(map[key]bool)
+
+	var buf bytes.Buffer
+	forEachAction(roots, func(act *checker.Action) {
+		if act.Err != nil {
+			fmt.Fprintf(&buf, "%s: %v\n", act.Analyzer.Name, act.Err)
+			return
+		}
+		if !act.IsRoot {
+			return
+		}
+
+		for _, diag := range act.Diagnostics {
+			posn, end := act.Package.Fset.Position(diag.Pos), act.Package.Fset.Position(diag.End)
+			k := key{posn, end, diag.Message}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			fmt.Fprintf(&buf, "%s: %s\n", base.posn(posn), diag.Message)
+			if contextLines < 0 {
+				continue
+			}
+			if !end.IsValid() {
+				end = posn
+			}
+			data, _ := os.ReadFile(posn.Filename)
+			lines := strings.Split(string(data), "\n")
+			for i := posn.Line - contextLines; i <= end.Line+contextLines; i++ {
+				if 1 <= i && i <= len(lines) {
+					fmt.Fprintf(&buf, "%d\t%s\n", i, lines[i-1])
+				}
+			}
+		}
+	})
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// printJSON prints the diagnostics of roots, and the errors of analyses, like singlechecker:
+// a JSON object keyed by package ID, then analyzer name. File paths are made relative to
+// base.
+func printJSON(w io.Writer, roots []*checker.Action, base baseDir) error {
+	type jsonError struct {
+		Err string `json:"error"`
+	}
+
+	tree := make(map[string]map[string]any)
+	forEachAction(roots, func(act *checker.Action) {
+		var v any
+		switch {
+		case act.Err != nil:
+			v = jsonError{act.Err.Error()}
+		case act.IsRoot && len(act.Diagnostics) > 0:
+			v = jsonDiagnostics(act.Package.Fset, act.Diagnostics, base)
+		default:
+			return
+		}
+
+		if tree[act.Package.ID] == nil {
+			tree[act.Package.ID] = make(map[string]any)
+		}
+		tree[act.Package.ID][act.Analyzer.Name] = v
+	})
+
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// jsonDiagnostics converts diags to their JSON format, with file paths relative to base.
+func jsonDiagnostics(fset *token.FileSet, diags []analysis.Diagnostic, base baseDir) []jsonDiagnostic {
+	out := make([]jsonDiagnostic, 0, len(diags))
+	for _, diag := range diags {
+		jdiag := jsonDiagnostic{
+			Category: diag.Category,
+			Posn:     base.posn(fset.Position(diag.Pos)),
+			Message:  diag.Message,
+		}
+		for _, fix := range diag.SuggestedFixes {
+			jfix := jsonSuggestedFix{Message: fix.Message}
+			for _, edit := range fix.TextEdits {
+				jfix.Edits = append(jfix.Edits, jsonTextEdit{
+					Filename: base.rel(fset.Position(edit.Pos).Filename),
+					Start:    fset.Position(edit.Pos).Offset,
+					End:      fset.Position(edit.End).Offset,
+					New:      string(edit.NewText),
+				})
+			}
+			jdiag.SuggestedFixes = append(jdiag.SuggestedFixes, jfix)
+		}
+		for _, r := range diag.Related {
+			jdiag.Related = append(jdiag.Related, jsonRelated{
+				Posn:    base.posn(fset.Position(r.Pos)),
+				Message: r.Message,
+			})
+		}
+		out = append(out, jdiag)
+	}
+	return out
+}
@@ -3,9 +3,16 @@ package spancheck
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 )
 
 // Check is a type of check that can be enabled or disabled.
@@ -20,6 +27,87 @@ const (
 
 	// RecordErrorCheck if enabled, checks that span.RecordError(err) is called when returning an error.
 	RecordErrorCheck
+
+	// EndBeforeGoroutineCheck if enabled, checks that span.End() is not called before a goroutine
+	// that was passed the span's context completes.
+	EndBeforeGoroutineCheck
+
+	// RequestContextCheck if enabled, checks that a span started from an HTTP request's context is
+	// propagated with `r.WithContext(ctx)` when the request is passed on, eg to the next handler.
+	RequestContextCheck
+
+	// UseAfterEndCheck if enabled, checks that span.SetStatus(), span.RecordError(), span.SetAttributes()
+	// and other span operations are not called after span.End(), where they have no effect.
+	UseAfterEndCheck
+
+	// ReturnedContextCheck if enabled, checks that a span's context is not returned from a function
+	// that ends the span in a defer, since the caller would get a context with an ended span.
+	ReturnedContextCheck
+
+	// EndStyleCheck if enabled, checks that spans in a file are ended in a consistent style,
+	// either all with a deferred span.End() or all with a direct call.
+	EndStyleCheck
+
+	// DeferOrderCheck if enabled, checks that a deferred span.SetStatus(), span.RecordError() or
+	// other span operation is not registered before the deferred span.End(), since defers run in
+	// reverse order and the operation would run after the span is ended.
+	DeferOrderCheck
+
+	// NilTracerCheck if enabled, checks that a tracer that may be nil, like one in a struct field,
+	// is checked against nil before it is used to start a span.
+	NilTracerCheck
+
+	// IsRecordingCheck if enabled, checks that span.SetAttributes() and span.AddEvent() calls whose
+	// arguments call expensive functions are guarded by span.IsRecording().
+	IsRecordingCheck
+
+	// MustHaveSpanCheck if enabled, checks that functions taking a context.Context as their first
+	// parameter start a span.
+	MustHaveSpanCheck
+
+	// DoubleEndCheck if enabled, checks that span.End() is not called directly after it is
+	// deferred, which ends the span twice.
+	DoubleEndCheck
+
+	// LockedEndCheck if enabled, checks that span.End() is not called while a sync.Mutex or
+	// sync.RWMutex is locked, where a blocking exporter would hold up the lock.
+	LockedEndCheck
+
+	// SpanTypeCheck if enabled, checks that spans are ended through a variable of the same type
+	// they were started as, rather than an interface that drops some of their methods.
+	SpanTypeCheck
+
+	// TracerNameCheck if enabled, checks that tracers are created with the name expected for
+	// their package, eg otel.Tracer("github.com/org/repo/pkg").
+	TracerNameCheck
+
+	// LoopSpanCheck if enabled, checks that spans are not started unconditionally in the body
+	// of a loop, where a span every iteration can overwhelm the collector.
+	LoopSpanCheck
+
+	// UnusedContextCheck if enabled, checks that the context returned by starting a span is
+	// used, rather than the context the span was started from.
+	UnusedContextCheck
+
+	// EndErrorCheck if enabled, checks that errors returned by span.End() are not dropped,
+	// for span wrappers whose End returns an error.
+	EndErrorCheck
+
+	// SpanHelperCheck if enabled, checks that span.SetStatus(), span.RecordError() and
+	// span.SetAttributes() are only called directly in the packages of span helpers.
+	SpanHelperCheck
+
+	// DeadSpanCheck if enabled, checks for spans that are ended without their context being
+	// used or anything being recorded on them.
+	DeadSpanCheck
+
+	// LateSpanCheck if enabled, checks that errors are not checked before the first span of a
+	// function is started, where their failures are not traced.
+	LateSpanCheck
+
+	// RecordErrorMismatchCheck if enabled, checks that span.RecordError(err) is not followed by a
+	// return of a different error, which is usually a copy-paste bug recording the wrong error.
+	RecordErrorMismatchCheck
 )
 
 var (
@@ -42,6 +130,100 @@ func (c Check) String() string {
 		return "set-status"
 	case RecordErrorCheck:
 		return "record-error"
+	case EndBeforeGoroutineCheck:
+		return "end-before-goroutine"
+	case RequestContextCheck:
+		return "request-context"
+	case UseAfterEndCheck:
+		return "use-after-end"
+	case ReturnedContextCheck:
+		return "returned-context"
+	case EndStyleCheck:
+		return "end-style"
+	case DeferOrderCheck:
+		return "defer-order"
+	case NilTracerCheck:
+		return "nil-tracer"
+	case IsRecordingCheck:
+		return "is-recording"
+	case MustHaveSpanCheck:
+		return "must-have-span"
+	case DoubleEndCheck:
+		return "double-end"
+	case LockedEndCheck:
+		return "locked-end"
+	case SpanTypeCheck:
+		return "span-type"
+	case TracerNameCheck:
+		return "tracer-name"
+	case LoopSpanCheck:
+		return "loop-span"
+	case UnusedContextCheck:
+		return "unused-context"
+	case EndErrorCheck:
+		return "end-error"
+	case SpanHelperCheck:
+		return "span-helper"
+	case DeadSpanCheck:
+		return "dead-span"
+	case LateSpanCheck:
+		return "late-span"
+	case RecordErrorMismatchCheck:
+		return "record-error-mismatch"
+	default:
+		return ""
+	}
+}
+
+// Description returns a one-line description of what the check reports.
+func (c Check) Description() string {
+	switch c {
+	case EndCheck:
+		return "span.End() is not called on all paths"
+	case SetStatusCheck:
+		return "span.SetStatus(codes.Error, msg) is not called when returning an error"
+	case RecordErrorCheck:
+		return "span.RecordError(err) is not called when returning an error"
+	case EndBeforeGoroutineCheck:
+		return "span.End() is called before a goroutine passed the span's context completes"
+	case RequestContextCheck:
+		return "a span's context is not propagated with r.WithContext(ctx) when the request is passed on"
+	case UseAfterEndCheck:
+		return "span operations are called after span.End()"
+	case ReturnedContextCheck:
+		return "a span's context is returned from a function that ends the span in a defer"
+	case EndStyleCheck:
+		return "spans in a file are ended both with a deferred and a direct span.End()"
+	case DeferOrderCheck:
+		return "a deferred span operation runs after the deferred span.End()"
+	case NilTracerCheck:
+		return "a tracer that may be nil is used without checking it against nil"
+	case IsRecordingCheck:
+		return "expensive span attributes are not guarded by span.IsRecording()"
+	case MustHaveSpanCheck:
+		return "a function taking a context.Context does not start a span"
+	case DoubleEndCheck:
+		return "span.End() is called directly after it is deferred"
+	case LockedEndCheck:
+		return "span.End() is called while a mutex is locked"
+	case SpanTypeCheck:
+		return "a span is ended through a variable of another type"
+	case TracerNameCheck:
+		return "a tracer is not created with the name expected for its package"
+	case LoopSpanCheck:
+		return "a span is started unconditionally in a loop"
+	case UnusedContextCheck:
+		return "the context returned by starting a span is not used"
+	case EndErrorCheck:
+		return "the error returned by span.End() is dropped"
+	case SpanHelperCheck:
+		return "span methods are called directly outside span helper packages"
+	case DeadSpanCheck:
+		return "a span is only ended, without using its context or recording anything on it"
+	case LateSpanCheck:
+		return "an error is checked before the first span of a function is started"
+	case RecordErrorMismatchCheck:
+		return "span.RecordError(err) records a different error than the one returned"
 	default:
 		return ""
 	}
@@ -52,6 +234,27 @@ var Checks = map[string]Check{
 	EndCheck.String():         EndCheck,
 	SetStatusCheck.String():   SetStatusCheck,
 	RecordErrorCheck.String(): RecordErrorCheck,
+
+	EndBeforeGoroutineCheck.String():  EndBeforeGoroutineCheck,
+	RequestContextCheck.String():      RequestContextCheck,
+	UseAfterEndCheck.String():         UseAfterEndCheck,
+	ReturnedContextCheck.String():     ReturnedContextCheck,
+	EndStyleCheck.String():            EndStyleCheck,
+	DeferOrderCheck.String():          DeferOrderCheck,
+	NilTracerCheck.String():           NilTracerCheck,
+	IsRecordingCheck.String():         IsRecordingCheck,
+	MustHaveSpanCheck.String():        MustHaveSpanCheck,
+	DoubleEndCheck.String():           DoubleEndCheck,
+	LockedEndCheck.String():           LockedEndCheck,
+	SpanTypeCheck.String():            SpanTypeCheck,
+	TracerNameCheck.String():          TracerNameCheck,
+	LoopSpanCheck.String():            LoopSpanCheck,
+	UnusedContextCheck.String():       UnusedContextCheck,
+	EndErrorCheck.String():            EndErrorCheck,
+	SpanHelperCheck.String():          SpanHelperCheck,
+	DeadSpanCheck.String():            DeadSpanCheck,
+	LateSpanCheck.String():            LateSpanCheck,
+	RecordErrorMismatchCheck.String(): RecordErrorMismatchCheck,
 }
 
 type spanStartMatcher struct {
@@ -63,6 +266,13 @@ type spanStartMatcher struct {
 type Config struct {
 	fs flag.FlagSet
 
+	// FlagPrefix is prepended to the names of the analyzer's flags, eg
+	// "spancheck." to register "-spancheck.checks". It must be set before the
+	// analyzer is created. This is only needed by drivers that merge analyzer
+	// flags into their own flag set, since multichecker already prefixes flags
+	// with the analyzer's name.
+	FlagPrefix string
+
 	// EnabledChecks is a list of checks to enable by name.
 	EnabledChecks []string
 
@@ -72,49 +282,529 @@ type Config struct {
 
 	StartSpanMatchersSlice []string
 
+	// RecordErrorSatisfiesSetStatus, if true, treats a call to span.RecordError
+	// as satisfying the SetStatus check. This relaxes OpenTelemetry's
+	// recommendation to always set an error status and is off by default.
+	RecordErrorSatisfiesSetStatus bool
+
+	// NoReturnFuncsSlice is a slice of strings that are turned into the
+	// noReturnFuncs regex.
+	NoReturnFuncsSlice []string
+
+	// EndFuncsSlice is a slice of strings that are turned into the endFuncs regex.
+	EndFuncsSlice []string
+
+	// DeferredEndFuncsSlice is a slice of strings that are turned into the
+	// deferredEndFuncs regex.
+	DeferredEndFuncsSlice []string
+
+	// EntryPointsSlice is a slice of strings that are turned into the entryPoints
+	// regex. If set, only spans in matching functions, and in the functions of the
+	// same package that they call, are checked.
+	EntryPointsSlice []string
+
+	// MustHaveSpanFuncsSlice is a slice of strings that are turned into the
+	// mustHaveSpanFuncs regex.
+	MustHaveSpanFuncsSlice []string
+
+	// ExcludePkgsSlice is a slice of strings that are turned into the excludePkgs
+	// regex. Packages whose import paths match are not analyzed.
+	ExcludePkgsSlice []string
+
+	// CheapAttributeFuncsSlice is a slice of strings that are turned into the
+	// cheapAttributeFuncs regex, along with defaultCheapAttributeFuncs.
+	CheapAttributeFuncsSlice []string
+
+	// SpanHelperPkgsSlice is a slice of strings that are turned into the
+	// spanHelperPkgs regex.
+	SpanHelperPkgsSlice []string
+
+	// PanicOnErrorFuncsSlice is a slice of strings that are turned into the
+	// panicOnErrorFuncs regex.
+	PanicOnErrorFuncsSlice []string
+
+	// IgnoreBuildTags are build constraint terms, eg "debug" or "!production".
+	// Spans in files whose //go:build line has one of them are not checked.
+	IgnoreBuildTags []string
+
+	// IgnoreSpanNameRegex is a regex that, if matched against the name a span is started
+	// with, eg "health-check", skips all checks of the span. Only constant names match.
+	IgnoreSpanNameRegex string
+
+	// SameFuncEnd, if true, requires spans to be ended in the function that
+	// starts them. Spans that are returned or passed elsewhere are reported.
+	SameFuncEnd bool
+
+	// SkipMainEnd, if true, skips the end check for spans started in the main
+	// function of package main. Those spans usually last as long as the process,
+	// and are ended at shutdown, eg in a signal handler.
+	SkipMainEnd bool
+
+	// RelaxTestFiles, if true, skips the set-status and record-error checks in
+	// _test.go files, where spans rarely need an error status. The end check
+	// still applies.
+	RelaxTestFiles bool
+
+	// AllPaths, if true, reports every return statement that can be reached
+	// without the required call, rather than only the first one found.
+	AllPaths bool
+
+	// StrictSetStatusCode, if true, reports span.SetStatus calls whose status code
+	// is not a constant, like codes.Error, since they cannot be verified to set an
+	// error status. By default, they satisfy the SetStatus check.
+	StrictSetStatusCode bool
+
+	// StrictRecordError, if true, requires span.RecordError to be called on
+	// every path to every error return, and reports each error return that can
+	// be reached without it. By default, only returns in the blocks nested
+	// below the span's definition are checked.
+	StrictRecordError bool
+
+	// StrictRecoveredErrors, if true, treats a deferred function that recovers a
+	// panic and assigns it to a named error result as an error path, so the
+	// set-status and record-error checks require the span call in that defer.
+	StrictRecoveredErrors bool
+
+	// DeferWithin, if positive, is the maximum number of statements from a span's
+	// start to its deferred End, eg 1 to require `defer span.End()` to be the next
+	// statement. Deferring End in another block is reported too.
+	DeferWithin int
+
+	// LoopSpanDepth is the number of nested loops a span must be started in for
+	// the loop-span check to report it, eg 2 to only report spans started in an
+	// inner loop. It defaults to 1.
+	LoopSpanDepth int
+
+	// MaxFuncNodes, if positive, skips functions whose control flow graph has
+	// more nodes than this. It guards against slow analysis of very large,
+	// usually generated, functions.
+	MaxFuncNodes int
+
+	// Explain, if true, writes a line for every span found and check run on it,
+	// saying whether the check passed and, if not, the path that led to the
+	// finding. It is meant for debugging configuration.
+	Explain bool
+
+	// ExplainOutput is where Explain writes to. It defaults to os.Stderr.
+	ExplainOutput io.Writer
+
+	// ReportFunc, if set, is called with each finding instead of reporting it
+	// as a diagnostic of the analysis pass. It must be safe for concurrent use,
+	// since packages can be analyzed in parallel.
+	ReportFunc func(Finding)
+
+	// DiffFile, if set, is the path of a unified diff, eg the output of `git diff`.
+	// Only findings on lines the diff adds or changes are reported.
+	DiffFile string
+
+	// TracerNameTemplate is a text/template for the name the tracer-name check expects
+	// tracers to be created with. It is executed with the package's import Path and Name,
+	// and defaults to "{{.Path}}".
+	TracerNameTemplate string
+
+	// Logger is where warnings about the configuration are written, eg an invalid
+	// signature. It defaults to log.Default().
+	Logger *log.Logger
+
+	// CustomChecks are run on each span started in a function, after the built-in checks.
+	// The diagnostics they return are reported like other findings, with the diagnostic's
+	// category as the check name. They must be safe for concurrent use.
+	CustomChecks []CustomCheck
+
+	registerFlagsOnce sync.Once
+	checksFlag        *commaSeparatedValue
+	finalizeOnce      sync.Once
+	explainMu         sync.Mutex
+
+	// checksFlagCloned is whether the checks flag was set on the config this one was
+	// cloned from, since a clone registers its own flags.
+	checksFlagCloned bool
+
+	// enabledChecks are EnabledChecks with the SPANCHECK_<CHECK> environment variables
+	// applied, as parsed by finalize.
+	enabledChecks []string
+
+	noChecksEnabled    bool
 	endCheckEnabled    bool
 	setStatusEnabled   bool
 	recordErrorEnabled bool
 
+	endBeforeGoroutineEnabled  bool
+	requestContextEnabled      bool
+	useAfterEndEnabled         bool
+	returnedContextEnabled     bool
+	endStyleEnabled            bool
+	deferOrderEnabled          bool
+	nilTracerEnabled           bool
+	isRecordingEnabled         bool
+	mustHaveSpanEnabled        bool
+	doubleEndEnabled           bool
+	lockedEndEnabled           bool
+	spanTypeEnabled            bool
+	tracerNameEnabled          bool
+	loopSpanEnabled            bool
+	unusedContextEnabled       bool
+	endErrorEnabled            bool
+	spanHelperEnabled          bool
+	deadSpanEnabled            bool
+	lateSpanEnabled            bool
+	recordErrorMismatchEnabled bool
+
+	// diff are the lines changed by DiffFile, or nil to report findings on any line.
+	diff changedLines
+	// diffErr is the error reading DiffFile, returned by every run instead of reporting
+	// findings on all lines.
+	diffErr error
+
+	// tracerName is the parsed TracerNameTemplate.
+	tracerName *template.Template
+
 	// ignoreChecksSignatures is a regex that, if matched, disables the
 	// SetStatus and RecordError checks on error.
 	ignoreChecksSignatures *regexp.Regexp
 
+	// noReturnFuncs is a regex that, if matched, marks a function call as never
+	// returning. The End check treats such calls like return statements.
+	noReturnFuncs *regexp.Regexp
+
+	// endFuncs is a regex that, if matched, marks a function call that is passed a span
+	// as ending it, eg FinishSpan(ctx, span).
+	endFuncs *regexp.Regexp
+
+	// deferredEndFuncs is a regex that, if matched, marks a function call in a defer that is
+	// passed a span as ending it, eg `defer finishSpan(span, &err)` or
+	// `defer func() { finishSpan(span, &err) }()`. If it is also passed a pointer to an error,
+	// it sets the span's status and records the error.
+	deferredEndFuncs *regexp.Regexp
+
+	// entryPoints is a regex that, if matched against a function's full name, marks it
+	// as an entry point, eg `(*example.com/api.Server).ServeHTTP`.
+	entryPoints *regexp.Regexp
+
+	// mustHaveSpanFuncs is a regex that, if set, limits the MustHaveSpan check to the
+	// functions whose full names match it.
+	mustHaveSpanFuncs *regexp.Regexp
+
+	// excludePkgs is a regex that, if matched against a package's import path, skips
+	// analyzing the package.
+	excludePkgs *regexp.Regexp
+
+	// spanHelperPkgs is a regex that, if matched against a package's import path, allows
+	// the package to call span methods that the span-helper check reports elsewhere.
+	spanHelperPkgs *regexp.Regexp
+
+	// cheapAttributeFuncs is a regex that, if matched, marks a function as cheap enough
+	// to call in the arguments of span.SetAttributes() without a span.IsRecording() guard.
+	cheapAttributeFuncs *regexp.Regexp
+
+	// panicOnErrorFuncs is a regex that, if matched, marks a function call as
+	// panicking on error, eg `must(err)`. The SetStatus and RecordError checks
+	// treat such calls like returning an error.
+	panicOnErrorFuncs *regexp.Regexp
+
+	// ignoreSpanName is the parsed IgnoreSpanNameRegex.
+	ignoreSpanName *regexp.Regexp
+
 	startSpanMatchers            []spanStartMatcher
 	startSpanMatchersCustomRegex *regexp.Regexp
 }
 
+// defaultChecks are the checks enabled by NewDefaultConfig.
+var defaultChecks = []string{EndCheck.String()}
+
 // NewDefaultConfig returns a new Config with default values.
 func NewDefaultConfig() *Config {
 	return &Config{
-		EnabledChecks:          []string{EndCheck.String()},
+		EnabledChecks:          slices.Clone(defaultChecks),
 		StartSpanMatchersSlice: defaultStartSpanSignatures,
 	}
 }
 
-// finalize parses checks and signatures from the public string slices of Config.
+// Clone returns a copy of the config that can be changed, and used to create another analyzer,
+// without affecting this one. Slices are copied, while the Logger, ExplainOutput, ReportFunc and
+// CustomChecks' functions are shared. The clone parses its signatures and checks itself, when
+// its analyzer first runs, so changes to the clone's fields take effect.
+func (c *Config) Clone() *Config {
+	return &Config{
+		FlagPrefix:                    c.FlagPrefix,
+		EnabledChecks:                 slices.Clone(c.EnabledChecks),
+		IgnoreChecksSignaturesSlice:   slices.Clone(c.IgnoreChecksSignaturesSlice),
+		StartSpanMatchersSlice:        slices.Clone(c.StartSpanMatchersSlice),
+		RecordErrorSatisfiesSetStatus: c.RecordErrorSatisfiesSetStatus,
+		NoReturnFuncsSlice:            slices.Clone(c.NoReturnFuncsSlice),
+		EndFuncsSlice:                 slices.Clone(c.EndFuncsSlice),
+		DeferredEndFuncsSlice:         slices.Clone(c.DeferredEndFuncsSlice),
+		EntryPointsSlice:              slices.Clone(c.EntryPointsSlice),
+		MustHaveSpanFuncsSlice:        slices.Clone(c.MustHaveSpanFuncsSlice),
+		ExcludePkgsSlice:              slices.Clone(c.ExcludePkgsSlice),
+		CheapAttributeFuncsSlice:      slices.Clone(c.CheapAttributeFuncsSlice),
+		SpanHelperPkgsSlice:           slices.Clone(c.SpanHelperPkgsSlice),
+		PanicOnErrorFuncsSlice:        slices.Clone(c.PanicOnErrorFuncsSlice),
+		IgnoreBuildTags:               slices.Clone(c.IgnoreBuildTags),
+		IgnoreSpanNameRegex:           c.IgnoreSpanNameRegex,
+		SameFuncEnd:                   c.SameFuncEnd,
+		SkipMainEnd:                   c.SkipMainEnd,
+		RelaxTestFiles:                c.RelaxTestFiles,
+		AllPaths:                      c.AllPaths,
+		StrictSetStatusCode:           c.StrictSetStatusCode,
+		StrictRecordError:             c.StrictRecordError,
+		StrictRecoveredErrors:         c.StrictRecoveredErrors,
+		DeferWithin:                   c.DeferWithin,
+		LoopSpanDepth:                 c.LoopSpanDepth,
+		MaxFuncNodes:                  c.MaxFuncNodes,
+		Explain:                       c.Explain,
+		ExplainOutput:                 c.ExplainOutput,
+		ReportFunc:                    c.ReportFunc,
+		DiffFile:                      c.DiffFile,
+		TracerNameTemplate:            c.TracerNameTemplate,
+		Logger:                        c.Logger,
+		CustomChecks:                  slices.Clone(c.CustomChecks),
+
+		checksFlagCloned: c.checksFlagSet(),
+	}
+}
+
+// registerFlags registers flags for the public fields of Config on its flag set.
+// Flags are parsed before the analyzer runs, so they override the fields' values.
+func (c *Config) registerFlags() {
+	c.checksFlag = &commaSeparatedValue{s: &c.EnabledChecks}
+	c.fs.Var(c.checksFlag, c.FlagPrefix+"checks", fmt.Sprintf("comma-separated list of checks to enable (options: %v)", strings.Join(checkNames(), ", ")))
+	c.fs.Var(&commaSeparatedValue{s: &c.IgnoreChecksSignaturesSlice}, c.FlagPrefix+"ignore-check-signatures", "comma-separated list of regex for function signatures that disable checks on errors")
+	c.fs.Var(&commaSeparatedValue{s: &c.StartSpanMatchersSlice, append: true}, c.FlagPrefix+"extra-start-span-signatures", "comma-separated list of regex:telemetry-type for function signatures that indicate the start of a span")
+	c.fs.Var(&commaSeparatedValue{s: &c.NoReturnFuncsSlice}, c.FlagPrefix+"no-return-funcs", "comma-separated list of regex for function signatures that never return")
+	c.fs.Var(&commaSeparatedValue{s: &c.EndFuncsSlice}, c.FlagPrefix+"end-funcs", "comma-separated list of regex for function signatures that end a span passed to them")
+	c.fs.Var(&commaSeparatedValue{s: &c.DeferredEndFuncsSlice}, c.FlagPrefix+"deferred-end-funcs", "comma-separated list of regex for function signatures that, when called in a defer or a deferred function literal, end a span passed to them, and set its status and record its error if passed a pointer to one")
+	c.fs.Var(&commaSeparatedValue{s: &c.EntryPointsSlice}, c.FlagPrefix+"entry-points", "comma-separated list of regex for names of functions whose spans, and those of functions they call in the same package, are the only ones checked")
+	c.fs.Var(&commaSeparatedValue{s: &c.MustHaveSpanFuncsSlice}, c.FlagPrefix+"must-have-span-funcs", "comma-separated list of regex for names of functions the must-have-span check applies to (default all functions taking a context.Context)")
+	c.fs.Var(&commaSeparatedValue{s: &c.ExcludePkgsSlice}, c.FlagPrefix+"exclude-pkgs", "comma-separated list of regex for import paths of packages to skip")
+	c.fs.Var(&commaSeparatedValue{s: &c.CheapAttributeFuncsSlice}, c.FlagPrefix+"cheap-attribute-funcs", "comma-separated list of regex for function signatures that the is-recording check treats as cheap to call, in addition to the defaults")
+	c.fs.Var(&commaSeparatedValue{s: &c.SpanHelperPkgsSlice}, c.FlagPrefix+"span-helper-pkgs", "comma-separated list of regex for import paths of packages that the span-helper check allows to call span methods directly")
+	c.fs.Var(&commaSeparatedValue{s: &c.PanicOnErrorFuncsSlice}, c.FlagPrefix+"panic-on-error-funcs", "comma-separated list of regex for function signatures that panic on error")
+	c.fs.Var(&commaSeparatedValue{s: &c.IgnoreBuildTags}, c.FlagPrefix+"ignore-build-tags", "comma-separated list of build constraint terms, eg debug or !production, of files whose spans are not checked")
+	c.fs.BoolVar(&c.RecordErrorSatisfiesSetStatus, c.FlagPrefix+"record-error-satisfies-set-status", c.RecordErrorSatisfiesSetStatus, "treat a call to span.RecordError as satisfying the set-status check")
+	c.fs.BoolVar(&c.SameFuncEnd, c.FlagPrefix+"same-func-end", c.SameFuncEnd, "require spans to be ended in the function that starts them")
+	c.fs.BoolVar(&c.SkipMainEnd, c.FlagPrefix+"skip-main-end", c.SkipMainEnd, "skip the end check for spans started in the main function of package main")
+	c.fs.BoolVar(&c.RelaxTestFiles, c.FlagPrefix+"relax-test-files", c.RelaxTestFiles, "skip the set-status and record-error checks in _test.go files, keeping the end check")
+	c.fs.BoolVar(&c.AllPaths, c.FlagPrefix+"all-paths", c.AllPaths, "report every return that can be reached without the required span call, not just the first")
+	c.fs.BoolVar(&c.StrictSetStatusCode, c.FlagPrefix+"strict-set-status-code", c.StrictSetStatusCode, "report span.SetStatus calls with a status code that is not a constant, which cannot be verified to be an error")
+	c.fs.BoolVar(&c.StrictRecordError, c.FlagPrefix+"strict-record-error", c.StrictRecordError, "require span.RecordError on every path to every error return, reporting each one without it")
+	c.fs.BoolVar(&c.StrictRecoveredErrors, c.FlagPrefix+"strict-recovered-errors", c.StrictRecoveredErrors, "treat a deferred recover that returns a panic as an error as an error path for the set-status and record-error checks")
+	c.fs.StringVar(&c.TracerNameTemplate, c.FlagPrefix+"tracer-name-template", c.TracerNameTemplate, "text/template, with the package's .Path and .Name, for the name the tracer-name check expects tracers to be created with (default \"{{.Path}}\")")
+	c.fs.StringVar(&c.IgnoreSpanNameRegex, c.FlagPrefix+"ignore-span-name-regex", c.IgnoreSpanNameRegex, "regex for names of spans, eg health-check, that are not checked")
+	c.fs.StringVar(&c.DiffFile, c.FlagPrefix+"diff-file", c.DiffFile, "path of a unified diff, eg from git diff, to only report findings on the lines it adds or changes")
+	c.fs.BoolVar(&c.Explain, c.FlagPrefix+"explain", c.Explain, "print to stderr why each span passed or failed each check")
+	c.fs.IntVar(&c.DeferWithin, c.FlagPrefix+"defer-within", c.DeferWithin, "maximum number of statements from a span's start to its deferred End, eg 1 for the next statement (0 for no limit)")
+	c.fs.IntVar(&c.LoopSpanDepth, c.FlagPrefix+"loop-span-depth", c.LoopSpanDepth, "number of nested loops a span must be started in for the loop-span check to report it (default 1)")
+	c.fs.IntVar(&c.MaxFuncNodes, c.FlagPrefix+"max-func-nodes", c.MaxFuncNodes, "skip functions with more control flow graph nodes than this (0 for no limit)")
+}
+
+// commaSeparatedValue is a flag.Value that sets, or appends to, a string slice
+// from a comma-separated list.
+type commaSeparatedValue struct {
+	s      *[]string
+	append bool
+	added  []string
+	set    bool // the flag was set
+}
+
+func (v *commaSeparatedValue) String() string {
+	if v.append {
+		return strings.Join(v.added, ",")
+	}
+	if v.s == nil {
+		return ""
+	}
+	return strings.Join(*v.s, ",")
+}
+
+func (v *commaSeparatedValue) Set(s string) error {
+	v.set = true
+	values := strings.Split(s, ",")
+	if v.append {
+		v.added = append(v.added, values...)
+		*v.s = append(*v.s, values...)
+		return nil
+	}
+
+	*v.s = values
+	return nil
+}
+
+// finalize parses checks and signatures from the public string slices of Config. It is run
+// once, by the first analyzer created from the config to run.
 func (c *Config) finalize() {
 	c.parseSignatures()
 
-	checks := parseChecks(c.EnabledChecks)
+	c.parseDiff()
+	c.parseTracerNameTemplate()
+
+	c.enabledChecks = c.getEnvChecks()
+
+	checks := parseChecks(c.enabledChecks)
+	c.noChecksEnabled = len(checks) == 0
+	if c.noChecksEnabled && len(c.CustomChecks) == 0 {
+		c.logger().Printf("[WARN] no checks are enabled, so nothing is reported. checks %q, expected some of %s\n",
+			strings.Join(c.enabledChecks, ","), strings.Join(checkNames(), ", "))
+	}
 	c.endCheckEnabled = contains(checks, EndCheck)
 	c.setStatusEnabled = contains(checks, SetStatusCheck)
 	c.recordErrorEnabled = contains(checks, RecordErrorCheck)
+	c.endBeforeGoroutineEnabled = contains(checks, EndBeforeGoroutineCheck)
+	c.requestContextEnabled = contains(checks, RequestContextCheck)
+	c.useAfterEndEnabled = contains(checks, UseAfterEndCheck)
+	c.returnedContextEnabled = contains(checks, ReturnedContextCheck)
+	c.endStyleEnabled = contains(checks, EndStyleCheck)
+	c.deferOrderEnabled = contains(checks, DeferOrderCheck)
+	c.nilTracerEnabled = contains(checks, NilTracerCheck)
+	c.isRecordingEnabled = contains(checks, IsRecordingCheck)
+	c.mustHaveSpanEnabled = contains(checks, MustHaveSpanCheck)
+	c.doubleEndEnabled = contains(checks, DoubleEndCheck)
+	c.lockedEndEnabled = contains(checks, LockedEndCheck)
+	c.spanTypeEnabled = contains(checks, SpanTypeCheck)
+	c.tracerNameEnabled = contains(checks, TracerNameCheck)
+	c.loopSpanEnabled = contains(checks, LoopSpanCheck)
+	c.unusedContextEnabled = contains(checks, UnusedContextCheck)
+	c.endErrorEnabled = contains(checks, EndErrorCheck)
+	c.spanHelperEnabled = contains(checks, SpanHelperCheck)
+	c.deadSpanEnabled = contains(checks, DeadSpanCheck)
+	c.lateSpanEnabled = contains(checks, LateSpanCheck)
+	c.recordErrorMismatchEnabled = contains(checks, RecordErrorMismatchCheck)
+	if c.spanHelperEnabled && c.spanHelperPkgs == nil {
+		c.logger().Printf("[WARN] no span helper packages are set, so the span-helper check reports every direct span call\n")
+	}
 }
 
 // parseSignatures sets the Ignore*CheckSignatures regex from the string slices.
 func (c *Config) parseSignatures() {
 	c.parseIgnoreSignatures()
+	c.parseNoReturnSignatures()
+	c.parseEndFuncSignatures()
+	c.parseDeferredEndFuncSignatures()
+	c.parseEntryPoints()
+	c.parseExcludePkgs()
+	c.parseMustHaveSpanFuncs()
+	c.parseCheapAttributeFuncs()
+	c.parsePanicOnErrorSignatures()
+	c.parseSpanHelperPkgs()
+	c.parseIgnoreSpanName()
 	c.parseStartSpanSignatures()
 }
 
+// parseTracerNameTemplate parses the TracerNameTemplate, falling back to the default if it
+// is invalid.
+func (c *Config) parseTracerNameTemplate() {
+	if c.tracerName != nil {
+		return
+	}
+
+	text := c.TracerNameTemplate
+	if text == "" {
+		text = defaultTracerNameTemplate
+	}
+
+	tmpl, err := template.New("tracer-name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		c.logger().Printf("[WARN] failed to parse tracer name template %q, using %q: %v\n", text, defaultTracerNameTemplate, err)
+		tmpl = template.Must(template.New("tracer-name").Parse(defaultTracerNameTemplate))
+	}
+	c.tracerName = tmpl
+}
+
+func (c *Config) parseDiff() {
+	if c.diff != nil || c.DiffFile == "" {
+		return
+	}
+
+	diff, err := readDiff(c.DiffFile)
+	if err != nil {
+		c.diffErr = fmt.Errorf("failed to read diff %s: %w", c.DiffFile, err)
+		return
+	}
+	c.diff = diff
+}
+
 func (c *Config) parseIgnoreSignatures() {
-	if c.ignoreChecksSignatures == nil && len(c.IgnoreChecksSignaturesSlice) > 0 {
-		if len(c.IgnoreChecksSignaturesSlice) == 1 && c.IgnoreChecksSignaturesSlice[0] == "" {
-			return
+	if c.ignoreChecksSignatures == nil {
+		c.ignoreChecksSignatures = c.compileSlice(&c.IgnoreChecksSignaturesSlice)
+	}
+}
+
+func (c *Config) parseNoReturnSignatures() {
+	if c.noReturnFuncs == nil {
+		c.noReturnFuncs = c.compileSlice(&c.NoReturnFuncsSlice)
+	}
+}
+
+func (c *Config) parseEndFuncSignatures() {
+	if c.endFuncs == nil {
+		c.endFuncs = c.compileSlice(&c.EndFuncsSlice)
+	}
+}
+
+func (c *Config) parseDeferredEndFuncSignatures() {
+	if c.deferredEndFuncs == nil {
+		c.deferredEndFuncs = c.compileSlice(&c.DeferredEndFuncsSlice)
+	}
+}
+
+func (c *Config) parseEntryPoints() {
+	if c.entryPoints == nil {
+		c.entryPoints = c.compileSlice(&c.EntryPointsSlice)
+	}
+}
+
+func (c *Config) parseExcludePkgs() {
+	if c.excludePkgs == nil {
+		c.excludePkgs = c.compileSlice(&c.ExcludePkgsSlice)
+	}
+}
+
+func (c *Config) parseSpanHelperPkgs() {
+	if c.spanHelperPkgs == nil {
+		c.spanHelperPkgs = c.compileSlice(&c.SpanHelperPkgsSlice)
+	}
+}
+
+func (c *Config) parseIgnoreSpanName() {
+	if c.ignoreSpanName != nil || c.IgnoreSpanNameRegex == "" {
+		return
+	}
+
+	regex, err := regexp.Compile(c.IgnoreSpanNameRegex)
+	if err != nil {
+		c.logger().Printf("[WARN] failed to compile ignore span name regex %q, checking all spans: %v\n", c.IgnoreSpanNameRegex, err)
+		return
+	}
+	c.ignoreSpanName = regex
+}
+
+// isIgnoredSpanName reports whether the span started with the constant name is not checked.
+func (c *Config) isIgnoredSpanName(name string) bool {
+	return c.ignoreSpanName != nil && name != "" && c.ignoreSpanName.MatchString(name)
+}
+
+func (c *Config) parseMustHaveSpanFuncs() {
+	if c.mustHaveSpanFuncs == nil {
+		c.mustHaveSpanFuncs = c.compileSlice(&c.MustHaveSpanFuncsSlice)
+	}
+}
+
+func (c *Config) parseCheapAttributeFuncs() {
+	if c.cheapAttributeFuncs == nil {
+		sigs := append([]string{}, defaultCheapAttributeFuncs...)
+		for _, sig := range c.CheapAttributeFuncsSlice {
+			if sig != "" {
+				sigs = append(sigs, sig)
+			}
 		}
 
-		c.ignoreChecksSignatures = createRegex(c.IgnoreChecksSignaturesSlice)
+		c.cheapAttributeFuncs = c.createRegex(sigs)
+	}
+}
+
+func (c *Config) parsePanicOnErrorSignatures() {
+	if c.panicOnErrorFuncs == nil {
+		c.panicOnErrorFuncs = c.compileSlice(&c.PanicOnErrorFuncsSlice)
 	}
 }
 
@@ -129,14 +819,14 @@ func (c *Config) parseStartSpanSignatures() {
 
 		// Make sure we have both a signature and a telemetry type
 		if len(parts) != startSpanSignatureCols {
-			log.Default().Printf("[WARN] invalid start span signature \"%s\". expected regex:telemetry-type\n", sig)
+			c.logger().Printf("[WARN] invalid start span signature \"%s\". expected regex:telemetry-type\n", sig)
 
 			continue
 		}
 
 		sig, sigType := parts[0], parts[1]
 		if len(sig) < 1 {
-			log.Default().Print("[WARN] invalid start span signature, empty pattern")
+			c.logger().Print("[WARN] invalid start span signature, empty pattern")
 
 			continue
 		}
@@ -148,7 +838,7 @@ func (c *Config) parseStartSpanSignatures() {
 				validSpanTypes = append(validSpanTypes, k)
 			}
 
-			log.Default().
+			c.logger().
 				Printf("[WARN] invalid start span type \"%s\". expected one of %s\n", sigType, strings.Join(validSpanTypes, ", "))
 
 			continue
@@ -156,7 +846,7 @@ func (c *Config) parseStartSpanSignatures() {
 
 		regex, err := regexp.Compile(sig)
 		if err != nil {
-			log.Default().Printf("[WARN] failed to compile regex from signature %s: %v\n", sig, err)
+			c.logger().Printf("[WARN] failed to compile regex from signature %s: %v\n", sig, err)
 
 			continue
 		}
@@ -171,7 +861,7 @@ func (c *Config) parseStartSpanSignatures() {
 		}
 	}
 
-	c.startSpanMatchersCustomRegex = createRegex(customMatchers)
+	c.startSpanMatchersCustomRegex = c.createRegex(customMatchers)
 }
 
 func parseChecks(checksSlice []string) []Check {
@@ -197,7 +887,17 @@ func parseChecks(checksSlice []string) []Check {
 	return checks
 }
 
-func createRegex(sigs []string) *regexp.Regexp {
+// compileSlice compiles the signatures of a slice flag into one regex, or returns nil if
+// there are none, including when the flag is set to an empty string.
+func (c *Config) compileSlice(sigs *[]string) *regexp.Regexp {
+	if len(*sigs) == 0 || len(*sigs) == 1 && (*sigs)[0] == "" {
+		return nil
+	}
+
+	return c.createRegex(*sigs)
+}
+
+func (c *Config) createRegex(sigs []string) *regexp.Regexp {
 	if len(sigs) == 0 {
 		return nil
 	}
@@ -205,13 +905,82 @@ func createRegex(sigs []string) *regexp.Regexp {
 	regex := fmt.Sprintf("(%s)", strings.Join(sigs, "|"))
 	regexCompiled, err := regexp.Compile(regex)
 	if err != nil {
-		log.Default().Print("[WARN] failed to compile regex from signature flag", "regex", regex, "err", err)
+		c.logger().Print("[WARN] failed to compile regex from signature flag", "regex", regex, "err", err)
 		return nil
 	}
 
 	return regexCompiled
 }
 
+// logger returns the config's Logger, or the default logger if it is not set.
+func (c *Config) logger() *log.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return log.Default()
+}
+
+// checksFlagSet reports whether EnabledChecks were set by the checks flag of this config, or
+// of the config it was cloned from.
+func (c *Config) checksFlagSet() bool {
+	return c.checksFlagCloned || c.checksFlag != nil && c.checksFlag.set
+}
+
+// getEnvChecks returns EnabledChecks with the checks enabled or disabled by the
+// SPANCHECK_<CHECK> environment variables, eg SPANCHECK_SET_STATUS=1 or SPANCHECK_END=0,
+// without changing the config. The variables only apply to the default checks: the checks
+// flag and EnabledChecks changed in the config take precedence over them.
+func (c *Config) getEnvChecks() []string {
+	checks := c.EnabledChecks
+	if c.checksFlagSet() || !slices.Equal(checks, defaultChecks) {
+		return checks
+	}
+
+	for _, name := range checkNames() {
+		key := checkEnvVar(name)
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			c.logger().Printf("[WARN] invalid %s=%q, expected a boolean like 1 or 0\n", key, value)
+			continue
+		}
+
+		has := slices.Contains(checks, name)
+		if enabled && !has {
+			checks = append(slices.Clone(checks), name)
+		} else if !enabled && has {
+			checks = slices.DeleteFunc(slices.Clone(checks), func(s string) bool { return s == name })
+		}
+	}
+	return checks
+}
+
+// CheckEnabled reports whether check is enabled by EnabledChecks and the SPANCHECK_<CHECK>
+// environment variables, as it is when the analyzer runs. Call it after flags are parsed.
+func (c *Config) CheckEnabled(check Check) bool {
+	return contains(parseChecks(c.getEnvChecks()), check)
+}
+
+// checkEnvVar returns the environment variable that enables or disables the check name, eg
+// SPANCHECK_SET_STATUS for set-status.
+func checkEnvVar(name string) string {
+	return "SPANCHECK_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// checkNames returns the names of all checks, sorted.
+func checkNames() []string {
+	names := make([]string, 0, len(Checks))
+	for check := range Checks {
+		names = append(names, check)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func contains(s []Check, e Check) bool {
 	for _, a := range s {
 		if a == e {
@@ -1,6 +1,9 @@
 package spancheck
 
 import (
+	"log"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -26,6 +29,9 @@ func Test_parseChecks(t *testing.T) {
 		"end,record-error,set-status": {
 			checks: []Check{EndCheck, RecordErrorCheck, SetStatusCheck},
 		},
+		"end,end-before-goroutine": {
+			checks: []Check{EndCheck, EndBeforeGoroutineCheck},
+		},
 	} {
 		flag, tc := flag, tc
 		t.Run(flag, func(t *testing.T) {
@@ -43,3 +49,165 @@ func Test_parseChecks(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_Description(t *testing.T) {
+	t.Parallel()
+
+	for name, check := range Checks {
+		if check.Description() == "" {
+			t.Errorf("Missing description of check=%s", name)
+		}
+	}
+}
+
+func TestConfig_Clone(t *testing.T) {
+	t.Parallel()
+
+	// Set every exported field, so fields added later must be cloned too.
+	c := NewDefaultConfig()
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString("foo")
+		case reflect.Bool:
+			f.SetBool(true)
+		case reflect.Int:
+			f.SetInt(1)
+		case reflect.Slice:
+			f.Set(reflect.MakeSlice(f.Type(), 1, 1))
+			if f.Index(0).Kind() == reflect.String {
+				f.Index(0).SetString("foo")
+			}
+		case reflect.Func:
+			f.Set(reflect.MakeFunc(f.Type(), func([]reflect.Value) []reflect.Value { return nil }))
+		case reflect.Ptr:
+			f.Set(reflect.New(f.Type().Elem()))
+		case reflect.Interface:
+			f.Set(reflect.ValueOf(&strings.Builder{}))
+		default:
+			t.Fatalf("Unexpected kind=%s of field %s", f.Kind(), v.Type().Field(i).Name)
+		}
+	}
+
+	clone := reflect.ValueOf(c.Clone()).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		got, want := clone.Field(i), v.Field(i)
+		switch want.Kind() {
+		case reflect.Func:
+			if got.Pointer() != want.Pointer() {
+				t.Errorf("Unexpected %s of clone", name)
+			}
+		case reflect.Slice:
+			if got.Len() != want.Len() || (got.Len() > 0 && got.Pointer() == want.Pointer()) {
+				t.Errorf("Unexpected %s of clone, want a copy of %v", name, want)
+			}
+		default:
+			if !reflect.DeepEqual(got.Interface(), want.Interface()) {
+				t.Errorf("Unexpected %s=%v of clone, want=%v", name, got, want)
+			}
+		}
+	}
+}
+
+func TestConfig_Clone_checksFlag(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultConfig()
+	c.registerFlags()
+	if err := c.fs.Parse([]string{"-checks", "end"}); err != nil {
+		t.Fatalf("Unexpected error parsing flags: %v", err)
+	}
+
+	// The clone's own checks flag is not set, but flags set on the config still take
+	// precedence over environment variables.
+	clone := c.Clone()
+	clone.registerFlags()
+	if !clone.checksFlagSet() || !clone.Clone().checksFlagSet() {
+		t.Error("Unexpected checks flag not set on clone")
+	}
+	if NewDefaultConfig().Clone().checksFlagSet() {
+		t.Error("Unexpected checks flag set on clone of default config")
+	}
+}
+
+func TestConfig_getEnvChecks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		env    map[string]string
+		checks []string
+		flags  []string
+		want   []string
+		warn   string
+	}{
+		"none": {
+			want: []string{"end"},
+		},
+		"enable": {
+			env:  map[string]string{"SPANCHECK_SET_STATUS": "1"},
+			want: []string{"end", "set-status"},
+		},
+		"enable and disable": {
+			env:  map[string]string{"SPANCHECK_END": "0", "SPANCHECK_RECORD_ERROR": "true", "SPANCHECK_USE_AFTER_END": "1"},
+			want: []string{"record-error", "use-after-end"},
+		},
+		"flags take precedence": {
+			env:   map[string]string{"SPANCHECK_END": "0", "SPANCHECK_SET_STATUS": "1"},
+			flags: []string{"-checks", "end,record-error"},
+			want:  []string{"end", "record-error"},
+		},
+		"config takes precedence": {
+			env:    map[string]string{"SPANCHECK_END": "0", "SPANCHECK_SET_STATUS": "1"},
+			checks: []string{"end", "record-error"},
+			want:   []string{"end", "record-error"},
+		},
+		"invalid": {
+			env:  map[string]string{"SPANCHECK_SET_STATUS": "yes"},
+			want: []string{"end"},
+			warn: `invalid SPANCHECK_SET_STATUS="yes"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			var buf strings.Builder
+			c := NewDefaultConfig()
+			c.Logger = log.New(&buf, "", 0)
+			if tc.checks != nil {
+				c.EnabledChecks = tc.checks
+			}
+			before := slices.Clone(c.EnabledChecks)
+			c.registerFlags()
+			if err := c.fs.Parse(tc.flags); err != nil {
+				t.Fatalf("Unexpected error parsing flags: %v", err)
+			}
+
+			for name, check := range Checks {
+				if got, want := c.CheckEnabled(check), slices.Contains(tc.want, name); got != want {
+					t.Errorf("Unexpected CheckEnabled(%s)=%t, want=%t", name, got, want)
+				}
+			}
+
+			if got := c.getEnvChecks(); !slices.Equal(got, tc.want) {
+				t.Errorf("Unexpected checks=%v, want=%v", got, tc.want)
+			}
+			if len(tc.flags) == 0 && !slices.Equal(c.EnabledChecks, before) {
+				t.Errorf("Unexpected EnabledChecks=%v changed by env, want=%v", c.EnabledChecks, before)
+			}
+			if got := buf.String(); !strings.Contains(got, tc.warn) || tc.warn == "" && got != "" {
+				t.Errorf("Unexpected warning=%q, want=%q", got, tc.warn)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportReturnedEndedContext reports a span's context being returned from a function that
+// ends the span in a defer. The deferred End runs before the caller can use the context, so
+// spans the caller starts from it would have an ended parent.
+func reportReturnedEndedContext(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar) {
+	ctx := getContextVar(pass.TypesInfo, sv.stmt)
+	if ctx == nil || getDeferredEnd(pass.TypesInfo, node, sv.vr) == nil {
+		return
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// Returns from function literals don't return from this function.
+			return n == node
+		case *ast.ReturnStmt:
+			if n.Pos() < sv.stmt.Pos() {
+				return false
+			}
+
+			for _, res := range n.Results {
+				if isContext(pass.TypesInfo.TypeOf(res)) && usesVar(pass.TypesInfo, res, ctx) {
+					config.report(pass, ReturnedContextCheck, res, "%s is returned but %s is ended by a deferred End before the caller can use it", ctx.Name(), sv.vr.Name())
+				}
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// isContext reports whether t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
@@ -0,0 +1,89 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportDeadSpan reports a span that is ended, but is otherwise unused: its context is
+// discarded or never used after the span is started, it is started without options like
+// attributes, and End is the only method called on it. Such spans are usually left over
+// instrumentation that only adds noise to traces.
+func reportDeadSpan(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar) {
+	lhs, call := getStartAssign(sv.stmt)
+	if len(lhs) != 2 || call == nil || len(call.Args) > 2 {
+		return // the context can't be told apart, or the span is started with options
+	}
+	if id, ok := lhs[0].(*ast.Ident); !ok {
+		return
+	} else if ctx, ok := pass.TypesInfo.ObjectOf(id).(*types.Var); ok && usedAfter(pass.TypesInfo, node, ctx, sv.stmt.End()) {
+		return // the span's context is used
+	}
+
+	ended, used := false, false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if isBlankAssign(n) {
+				return false
+			}
+		case *ast.SelectorExpr:
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Sel.Name == "End" && (pass.TypesInfo.Uses[id] == sv.vr || sv.isAlias(pass.TypesInfo, id)) {
+				ended = true
+				return false
+			}
+		case *ast.Ident:
+			if n.Pos() > sv.stmt.End() && (pass.TypesInfo.Uses[n] == sv.vr || sv.isAlias(pass.TypesInfo, n)) {
+				used = true
+			}
+		}
+		return !used
+	})
+
+	if ended && !used {
+		config.report(pass, DeadSpanCheck, sv.stmt, "%s is only ended, without using its context or recording anything on it%s", sv.vr.Name(), sv.label())
+	}
+}
+
+// getStartAssign returns the left-hand side of the statement that starts a span, and the
+// call starting it.
+func getStartAssign(stmt ast.Node) ([]ast.Expr, *ast.CallExpr) {
+	var lhs []ast.Expr
+	var rhs ast.Expr
+	switch stmt := stmt.(type) {
+	case *ast.ValueSpec:
+		for _, id := range stmt.Names {
+			lhs = append(lhs, id)
+		}
+		if len(stmt.Values) == 1 {
+			rhs = stmt.Values[0]
+		}
+	case *ast.AssignStmt:
+		lhs = stmt.Lhs
+		if len(stmt.Rhs) == 1 {
+			rhs = stmt.Rhs[0]
+		}
+	}
+
+	call, _ := ast.Unparen(rhs).(*ast.CallExpr)
+	return lhs, call
+}
+
+// usedAfter reports whether v is used in node after pos, ignoring assignments to the blank
+// identifier like isContextUsed.
+func usedAfter(info *types.Info, node ast.Node, v *types.Var, pos token.Pos) bool {
+	used := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && isBlankAssign(assign) {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && id.Pos() > pos && info.Uses[id] == v {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
@@ -0,0 +1,142 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// reportDeferOrder reports span operations in a defer that is registered before a deferred
+// span.End(). Defers run in the reverse order they are registered, so the operation runs
+// after the span is ended, where it has no effect.
+func reportDeferOrder(pass *analysis.Pass, config *Config, g *cfg.CFG, spanVars map[*ast.Ident]spanVar) {
+	if g == nil {
+		return
+	}
+
+	// Assigning a new span to the variable starts over.
+	defs := make(map[*types.Var]map[ast.Node]bool)
+	for _, sv := range spanVars {
+		if defs[sv.vr] == nil {
+			defs[sv.vr] = make(map[ast.Node]bool)
+		}
+		defs[sv.vr][sv.stmt] = true
+	}
+
+	reported := make(map[ast.Node]bool)
+	for v := range defs {
+		for _, b := range g.Blocks {
+			for i, n := range b.Nodes {
+				stmt, ok := n.(*ast.DeferStmt)
+				if !ok || deferEnds(pass.TypesInfo, stmt, v) {
+					continue
+				}
+
+				ops := getDeferredOperations(pass.TypesInfo, stmt, v)
+				if len(ops) == 0 || !reachesDeferredEnd(pass.TypesInfo, b, b.Nodes[i+1:], v, defs[v]) {
+					continue
+				}
+
+				for _, op := range ops {
+					if !reported[op] {
+						reported[op] = true
+						config.report(pass, DeferOrderCheck, op, "%s.%s is deferred before %s.End, so it runs after the span is ended", v.Name(), op.Sel.Name, v.Name())
+					}
+				}
+			}
+		}
+	}
+}
+
+// deferEnds reports whether the deferred call ends v, either directly with `defer v.End()`
+// or in a deferred function literal.
+func deferEnds(info *types.Info, stmt *ast.DeferStmt, v *types.Var) bool {
+	if isEndSelector(info, stmt.Call.Fun, v) {
+		return true
+	}
+
+	lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return false
+	}
+
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok || found {
+			return false
+		}
+		if isEndCall(info, n, v) {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// getDeferredOperations returns the span operations on v that are run by the deferred call,
+// either directly like `defer v.SetStatus(...)` or in a deferred function literal.
+func getDeferredOperations(info *types.Info, stmt *ast.DeferStmt, v *types.Var) []*ast.SelectorExpr {
+	var ops []*ast.SelectorExpr
+	isOp := func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if _, ok := spanOperations[sel.Sel.Name]; !ok {
+			return false
+		}
+		id, ok := ast.Unparen(sel.X).(*ast.Ident)
+		return ok && info.Uses[id] == v
+	}
+
+	if isOp(stmt.Call.Fun) {
+		return append(ops, stmt.Call.Fun.(*ast.SelectorExpr))
+	}
+
+	lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if isOp(n) {
+			ops = append(ops, n.(*ast.SelectorExpr))
+		}
+		return true
+	})
+	return ops
+}
+
+// reachesDeferredEnd reports whether a defer ending v can be reached from rest, the statements
+// of b after another defer. The search stops at statements in defs, which assign a new span to v.
+func reachesDeferredEnd(info *types.Info, b *cfg.Block, rest []ast.Node, v *types.Var, defs map[ast.Node]bool) bool {
+	seen := make(map[*cfg.Block]bool)
+
+	var search func(b *cfg.Block, nodes []ast.Node) bool
+	search = func(b *cfg.Block, nodes []ast.Node) bool {
+		for _, n := range nodes {
+			if defs[n] {
+				return false
+			}
+			if stmt, ok := n.(*ast.DeferStmt); ok && deferEnds(info, stmt, v) {
+				return true
+			}
+		}
+
+		for _, succ := range b.Succs {
+			if !seen[succ] {
+				seen[succ] = true
+				if search(succ, succ.Nodes) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return search(b, rest)
+}
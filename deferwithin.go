@@ -0,0 +1,59 @@
+package spancheck
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportDistantDefer reports a deferred End of the span sv that is more than DeferWithin
+// statements after the span is started, or is in another block. Deferring End right after
+// Start makes it easy to see that the span is ended.
+func reportDistantDefer(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar) {
+	var deferred *ast.DeferStmt
+	inspectFunc(node, func(n ast.Node) {
+		if stmt, ok := n.(*ast.DeferStmt); ok && deferred == nil && stmt.Pos() > sv.stmt.Pos() && deferEnds(pass.TypesInfo, stmt, sv.vr) {
+			deferred = stmt
+		}
+	})
+	if deferred == nil {
+		return // not ended in a defer
+	}
+
+	list, start := getStmtList(node, sv.stmt)
+	for i, stmt := range list[start+1:] {
+		if stmt != deferred {
+			continue
+		}
+		if i+1 > config.DeferWithin {
+			config.report(pass, EndCheck, deferred, "%s.End is deferred %d statements after the span is started, more than defer-within %d", sv.vr.Name(), i+1, config.DeferWithin)
+		}
+		return
+	}
+	config.report(pass, EndCheck, deferred, "%s.End is not deferred in the block the span is started in", sv.vr.Name())
+}
+
+// getStmtList returns the list of statements in node containing n, and the index of the
+// statement in it that contains n.
+func getStmtList(node ast.Node, n ast.Node) ([]ast.Stmt, int) {
+	var list []ast.Stmt
+	index := -1
+	inspectFunc(node, func(m ast.Node) {
+		var stmts []ast.Stmt
+		switch m := m.(type) {
+		case *ast.BlockStmt:
+			stmts = m.List
+		case *ast.CaseClause:
+			stmts = m.Body
+		case *ast.CommClause:
+			stmts = m.Body
+		}
+
+		for i, stmt := range stmts {
+			if stmt.Pos() <= n.Pos() && n.End() <= stmt.End() {
+				list, index = stmts, i // the innermost list is found last
+			}
+		}
+	})
+	return list, index
+}
@@ -0,0 +1,108 @@
+package spancheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// changedLines are the lines added or changed by a diff, by the file's absolute path.
+type changedLines map[string]map[int]bool
+
+// readDiff reads the lines added or changed by the unified diff in the file at path,
+// eg the output of `git diff`. Paths in the diff are relative to the root of the repository
+// containing the working directory, like git prints them, or to the working directory if it
+// is not in a repository.
+func readDiff(path string) (changedLines, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	changed, err := parseDiff(f)
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	root := repoRoot(wd)
+
+	resolved := make(changedLines, len(changed))
+	for file, lines := range changed {
+		resolved[filepath.Join(root, filepath.FromSlash(file))] = lines
+	}
+	return resolved, nil
+}
+
+// repoRoot returns the closest directory to dir, or dir itself, with a .git directory or
+// file, or dir if there is none.
+func repoRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// parseDiff parses the lines added or changed by a unified diff, by the file's path in the
+// diff.
+func parseDiff(r io.Reader) (changedLines, error) {
+	changed := make(changedLines)
+
+	var (
+		file string
+		line int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(strings.Fields(text[4:] + " ")[0], "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+			line = 0
+		case strings.HasPrefix(text, "@@ "):
+			// @@ -start,count +start,count @@
+			var fromStart, fromCount int
+			if _, err := fmt.Sscanf(text, "@@ -%d,%d +%d", &fromStart, &fromCount, &line); err != nil {
+				if _, err := fmt.Sscanf(text, "@@ -%d +%d", &fromStart, &line); err != nil {
+					return nil, fmt.Errorf("invalid hunk header %q", text)
+				}
+			}
+		case file == "" || line == 0:
+			// Before the first hunk of a file.
+		case strings.HasPrefix(text, "+"):
+			if changed[file] == nil {
+				changed[file] = make(map[int]bool)
+			}
+			changed[file][line] = true
+			line++
+		case strings.HasPrefix(text, "-"), strings.HasPrefix(text, `\`):
+			// Removed lines and "\ No newline at end of file" are not in the new file.
+		default:
+			line++
+		}
+	}
+
+	return changed, scanner.Err()
+}
+
+// contains reports whether line of filename, an absolute path, is changed.
+func (c changedLines) contains(filename string, line int) bool {
+	return c[filepath.Clean(filename)][line]
+}
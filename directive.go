@@ -0,0 +1,70 @@
+package spancheck
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// disableDirective disables checks for a function when in its doc comment, eg
+// `//spancheck:disable` to disable all checks or `//spancheck:disable end` to
+// disable only the listed checks.
+const disableDirective = "//spancheck:disable"
+
+// noEndDirective disables the end check for a span when on the line starting it, eg for
+// spans that are handed to a background system which ends them. The span's other checks,
+// like set-status and record-error, still apply.
+const noEndDirective = "//spancheck:noend"
+
+// getDisabledChecks returns the checks disabled by a disable directive in doc.
+// If the directive lists no checks, all checks are disabled.
+func getDisabledChecks(doc *ast.CommentGroup) map[Check]bool {
+	if doc == nil {
+		return nil
+	}
+
+	for _, c := range doc.List {
+		rest, ok := strings.CutPrefix(c.Text, disableDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+
+		disabled := make(map[Check]bool)
+		for _, name := range strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			if check, ok := Checks[name]; ok {
+				disabled[check] = true
+			}
+		}
+
+		if len(disabled) == 0 {
+			for _, check := range Checks {
+				disabled[check] = true
+			}
+		}
+		return disabled
+	}
+
+	return nil
+}
+
+// hasNoEndDirective reports whether the line starting the span at stmt has a noend
+// directive.
+func hasNoEndDirective(pass *analysis.Pass, stmt ast.Node) bool {
+	line := pass.Fset.Position(stmt.Pos()).Line
+	for _, file := range pass.Files {
+		if file.FileStart > stmt.Pos() || stmt.Pos() > file.FileEnd {
+			continue
+		}
+
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				rest, ok := strings.CutPrefix(c.Text, noEndDirective)
+				if ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') && pass.Fset.Position(c.Pos()).Line == line {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package spancheck
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func Test_getDisabledChecks(t *testing.T) {
+	t.Parallel()
+
+	all := make([]Check, 0, len(Checks))
+	for _, check := range Checks {
+		all = append(all, check)
+	}
+
+	for comment, tc := range map[string]struct {
+		checks []Check
+	}{
+		"// a comment": {
+			checks: nil,
+		},
+		"//spancheck:disabled": {
+			checks: nil,
+		},
+		"//spancheck:disable": {
+			checks: all,
+		},
+		"//spancheck:disable unknown": {
+			checks: all,
+		},
+		"//spancheck:disable end": {
+			checks: []Check{EndCheck},
+		},
+		"//spancheck:disable set-status,record-error": {
+			checks: []Check{SetStatusCheck, RecordErrorCheck},
+		},
+		"//spancheck:disable set-status record-error": {
+			checks: []Check{SetStatusCheck, RecordErrorCheck},
+		},
+	} {
+		comment, tc := comment, tc
+		t.Run(comment, func(t *testing.T) {
+			t.Parallel()
+			disabled := getDisabledChecks(&ast.CommentGroup{List: []*ast.Comment{{Text: comment}}})
+			if len(disabled) != len(tc.checks) {
+				t.Fatalf("Unexpected disabled checks length=%d, want=%d", len(disabled), len(tc.checks))
+			}
+			for _, check := range tc.checks {
+				if !disabled[check] {
+					t.Fatalf("Unexpected check=%s not disabled", check)
+				}
+			}
+		})
+	}
+}
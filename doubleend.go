@@ -0,0 +1,77 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// reportDoubleEnd reports direct calls to span.End() that can be reached after span.End()
+// is deferred, so the span is ended twice. This often happens when refactoring a function
+// to defer the End call.
+func reportDoubleEnd(pass *analysis.Pass, config *Config, g *cfg.CFG, spanVars map[*ast.Ident]spanVar) {
+	if g == nil {
+		return
+	}
+
+	// Assigning a new span to the variable starts over.
+	defs := make(map[*types.Var]map[ast.Node]bool)
+	for _, sv := range spanVars {
+		if defs[sv.vr] == nil {
+			defs[sv.vr] = make(map[ast.Node]bool)
+		}
+		defs[sv.vr][sv.stmt] = true
+	}
+
+	reported := make(map[ast.Node]bool)
+	for v := range defs {
+		for _, b := range g.Blocks {
+			for i, n := range b.Nodes {
+				stmt, ok := n.(*ast.DeferStmt)
+				if !ok || !deferEnds(pass.TypesInfo, stmt, v) {
+					continue
+				}
+
+				line := pass.Fset.Position(stmt.Pos()).Line
+				for _, end := range getEndCallsAfter(pass.TypesInfo, b, b.Nodes[i+1:], v, defs[v]) {
+					if !reported[end] {
+						reported[end] = true
+						config.report(pass, DoubleEndCheck, end, "%s.End is called, but is also deferred on line %d, so the span is ended twice", v.Name(), line)
+					}
+				}
+			}
+		}
+	}
+}
+
+// getEndCallsAfter returns the direct calls to v.End() that can be reached from rest, the
+// statements of b after v.End() is deferred. The search stops at statements in defs, which
+// assign a new span to v.
+func getEndCallsAfter(info *types.Info, b *cfg.Block, rest []ast.Node, v *types.Var, defs map[ast.Node]bool) []ast.Node {
+	var ends []ast.Node
+	seen := make(map[*cfg.Block]bool)
+
+	var search func(b *cfg.Block, nodes []ast.Node)
+	search = func(b *cfg.Block, nodes []ast.Node) {
+		for _, n := range nodes {
+			if defs[n] {
+				return
+			}
+			if isEndCall(info, n, v) {
+				ends = append(ends, n)
+			}
+		}
+
+		for _, succ := range b.Succs {
+			if !seen[succ] {
+				seen[succ] = true
+				search(succ, succ.Nodes)
+			}
+		}
+	}
+	search(b, rest)
+
+	return ends
+}
@@ -0,0 +1,58 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportIgnoredEndErrors reports calls to span.End() whose error result is dropped, because
+// the call is a statement, deferred or run in a goroutine. OpenTelemetry and OpenCensus spans'
+// End methods return nothing, so this only applies to wrappers whose End returns an error.
+// Assigning the error to the blank identifier, eg `_ = span.End()`, ignores it explicitly.
+// The check is gated on End's signature rather than on the start-span matcher, since which
+// spans return an error is a property of their type, not of how they are started.
+func reportIgnoredEndErrors(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	reported := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		if reported[sv.vr] {
+			continue // the span is reassigned, its End calls are already checked
+		}
+		reported[sv.vr] = true
+
+		ast.Inspect(node, func(n ast.Node) bool {
+			var call *ast.CallExpr
+			switch n := n.(type) {
+			case *ast.ExprStmt:
+				call, _ = ast.Unparen(n.X).(*ast.CallExpr)
+			case *ast.DeferStmt:
+				call = n.Call
+			case *ast.GoStmt:
+				call = n.Call
+			}
+			if call == nil || !isSpanEndCall(pass.TypesInfo, call, sv) {
+				return true
+			}
+
+			if slices.Contains(errorsByArg(pass, call), true) {
+				config.report(pass, EndErrorCheck, call, "error returned by %s.End is not checked", sv.vr.Name())
+			}
+			return true
+		})
+	}
+}
+
+// isSpanEndCall reports whether call is End on the span or one of its aliases.
+func isSpanEndCall(info *types.Info, call *ast.CallExpr, sv spanVar) bool {
+	if isEndSelector(info, call.Fun, sv.vr) {
+		return true
+	}
+	for v := range sv.aliases {
+		if isEndSelector(info, call.Fun, v) {
+			return true
+		}
+	}
+	return false
+}
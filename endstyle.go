@@ -0,0 +1,89 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// endCall is a call ending a span, either deferred or direct.
+type endCall struct {
+	node     ast.Node
+	vr       *types.Var
+	deferred bool
+}
+
+// getEndCalls returns the calls to End on the spans in spanVars, found in node. Calls in
+// deferred function literals count as deferred, calls in other function literals are skipped.
+func getEndCalls(info *types.Info, node ast.Node, spanVars map[*ast.Ident]spanVar) []endCall {
+	vars := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		vars[sv.vr] = true
+	}
+
+	var ends []endCall
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n == node
+		case *ast.DeferStmt:
+			for v := range vars {
+				if getDeferredEnd(info, n, v) != nil {
+					ends = append(ends, endCall{node: n, vr: v, deferred: true})
+				}
+			}
+			return false
+		case *ast.ExprStmt:
+			for v := range vars {
+				if isEndCall(info, n, v) {
+					ends = append(ends, endCall{node: n, vr: v})
+				}
+			}
+		}
+		return true
+	})
+	return ends
+}
+
+// reportInconsistentEndStyle reports the calls ending spans in the minority style of their file,
+// deferred or direct. Each span counts once, as deferred if any of its End calls is, so a span
+// ended directly on several paths does not outweigh others. When the styles are even, direct
+// calls are reported.
+func reportInconsistentEndStyle(pass *analysis.Pass, config *Config, ends []endCall) {
+	byFile := make(map[string][]endCall)
+	for _, end := range ends {
+		file := pass.Fset.File(end.node.Pos()).Name()
+		byFile[file] = append(byFile[file], end)
+	}
+
+	for _, ends := range byFile {
+		deferredSpans := make(map[*types.Var]bool)
+		for _, end := range ends {
+			deferredSpans[end.vr] = deferredSpans[end.vr] || end.deferred
+		}
+
+		deferred := 0
+		for _, isDeferred := range deferredSpans {
+			if isDeferred {
+				deferred++
+			}
+		}
+
+		reportDeferred := deferred < len(deferredSpans)-deferred
+		if deferred == 0 || deferred == len(deferredSpans) {
+			continue // consistent
+		}
+
+		for _, end := range ends {
+			switch {
+			case deferredSpans[end.vr] && reportDeferred:
+				if end.deferred {
+					config.report(pass, EndStyleCheck, end.node, "%s.End is deferred, but other spans in this file call End directly", end.vr.Name())
+				}
+			case !deferredSpans[end.vr] && !reportDeferred:
+				config.report(pass, EndStyleCheck, end.node, "%s.End is called directly, but other spans in this file defer it", end.vr.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// getReachableFuncs returns the function declarations of the package whose full names match
+// entryPoints, eg `(*example.com/api.Server).ServeHTTP`, and those they reach by calling or
+// referring to other functions of the package.
+func getReachableFuncs(pass *analysis.Pass, entryPoints *regexp.Regexp) map[*ast.FuncDecl]bool {
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	for _, file := range pass.Files {
+		for _, d := range file.Decls {
+			if decl, ok := d.(*ast.FuncDecl); ok {
+				if fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+					decls[fn] = decl
+				}
+			}
+		}
+	}
+
+	reachable := make(map[*ast.FuncDecl]bool)
+	var visit func(decl *ast.FuncDecl)
+	visit = func(decl *ast.FuncDecl) {
+		if reachable[decl] {
+			return
+		}
+		reachable[decl] = true
+
+		if decl.Body == nil {
+			return
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			// Functions passed as values, eg handlers, are reachable too.
+			if fn, ok := pass.TypesInfo.Uses[id].(*types.Func); ok {
+				if callee, ok := decls[fn.Origin()]; ok {
+					visit(callee)
+				}
+			}
+			return true
+		})
+	}
+
+	for fn, decl := range decls {
+		if entryPoints.MatchString(fn.FullName()) {
+			visit(decl)
+		}
+	}
+
+	return reachable
+}
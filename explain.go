@@ -0,0 +1,66 @@
+package spancheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// explainf writes a line explaining the result of check on the span sv, if Explain is set.
+func (c *Config) explainf(pass *analysis.Pass, check Check, sv spanVar, format string, args ...interface{}) {
+	if !c.Explain {
+		return
+	}
+
+	var out io.Writer = os.Stderr
+	if c.ExplainOutput != nil {
+		out = c.ExplainOutput
+	}
+
+	// Packages can be analyzed in parallel, keep lines whole.
+	c.explainMu.Lock()
+	defer c.explainMu.Unlock()
+
+	fmt.Fprintf(out, "%s: %s: [%s] %s\n", pass.Fset.Position(sv.stmt.Pos()), sv.vr.Name(), check, fmt.Sprintf(format, args...))
+}
+
+// explainPath returns a function that explains the path to a node reached without calling
+// selName on the span sv, or nil if Explain is not set.
+func (c *Config) explainPath(pass *analysis.Pass, check Check, sv spanVar, selName string) func(n ast.Node, path []*cfg.Block) {
+	if !c.Explain {
+		return nil
+	}
+
+	return func(n ast.Node, path []*cfg.Block) {
+		blocks := make([]string, 0, len(path))
+		for _, b := range path {
+			blocks = append(blocks, formatBlock(pass.Fset, b))
+		}
+
+		what := "return"
+		if call, ok := n.(*ast.CallExpr); ok {
+			what = "call to " + calleeName(pass.TypesInfo, call)
+		}
+
+		c.explainf(pass, check, sv, "%s.%s is not called on path %s to %s at line %d",
+			sv.vr.Name(), selName, strings.Join(blocks, " -> "), what, pass.Fset.Position(n.Pos()).Line)
+	}
+}
+
+// formatBlock formats a block of the CFG by its kind and starting line, eg IfThen@L12.
+func formatBlock(fset *token.FileSet, b *cfg.Block) string {
+	var n ast.Node = b.Stmt
+	if len(b.Nodes) > 0 {
+		n = b.Nodes[0]
+	}
+	if n == nil {
+		return b.Kind.String()
+	}
+	return fmt.Sprintf("%s@L%d", b.Kind, fset.Position(n.Pos()).Line)
+}
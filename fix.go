@@ -0,0 +1,84 @@
+package spancheck
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// getDeferEndFix returns a fix that defers span.End() right after the statement that starts
+// the span. It is only suggested when the span is never ended and is started by a statement of
+// a block outside of any loop, where the deferred End runs once, when the function returns.
+func getDeferEndFix(pass *analysis.Pass, node ast.Node, sv spanVar) []analysis.SuggestedFix {
+	stmt, ok := sv.stmt.(*ast.AssignStmt)
+	if !ok || len(sv.endCalls) > 0 || len(sv.deferredEndCalls) > 0 || !isBlockStmt(node, stmt) {
+		return nil
+	}
+
+	// Insert at the end of the line, after any comment on the statement.
+	file := pass.Fset.File(stmt.End())
+	line := file.Line(stmt.End())
+	if line >= file.LineCount() {
+		return nil
+	}
+	pos := file.LineStart(line+1) - 1
+
+	indent := getIndent(pass, stmt)
+	return []analysis.SuggestedFix{{
+		Message: "Defer " + sv.vr.Name() + ".End()",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     pos,
+			End:     pos,
+			NewText: []byte("\n" + indent + "defer " + sv.vr.Name() + ".End()"),
+		}},
+	}}
+}
+
+// isBlockStmt reports whether stmt is a statement of a block, or a case of a switch or select,
+// in node that is not in a loop or a nested function.
+func isBlockStmt(node ast.Node, stmt ast.Stmt) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n == node
+		case *ast.ForStmt, *ast.RangeStmt:
+			return false
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+		for _, s := range list {
+			if s == stmt {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// getIndent returns the leading whitespace of the line starting stmt, so fixes inserting lines
+// after it match its indentation, whether tabs or spaces. It defaults to a tab per column
+// before stmt if the file can't be read.
+func getIndent(pass *analysis.Pass, stmt ast.Stmt) string {
+	position := pass.Fset.Position(stmt.Pos())
+	fallback := strings.Repeat("\t", position.Column-1)
+	if pass.ReadFile == nil {
+		return fallback
+	}
+
+	src, err := pass.ReadFile(position.Filename)
+	if err != nil || position.Offset > len(src) {
+		return fallback
+	}
+
+	start := pass.Fset.Position(pass.Fset.File(stmt.Pos()).LineStart(position.Line)).Offset
+	line := string(src[start:position.Offset])
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,168 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// reportEndBeforeGoroutine reports calls to span.End() that can run after a goroutine was
+// passed the span's context, but before it completes. Spans started by the goroutine would
+// have an ended parent. A call to a Wait method, eg sync.WaitGroup.Wait, is assumed to wait
+// for the goroutine to complete.
+func reportEndBeforeGoroutine(pass *analysis.Pass, config *Config, node ast.Node, g *cfg.CFG, sv spanVar) {
+	ctx := getContextVar(pass.TypesInfo, sv.stmt)
+	if ctx == nil || g == nil {
+		return
+	}
+
+	defBlock, rest := getDefBlock(g, sv.stmt)
+	if defBlock == nil {
+		return
+	}
+
+	// A deferred End runs when the function returns.
+	deferredEnd := getDeferredEnd(pass.TypesInfo, node, sv.vr)
+
+	reported := make(map[ast.Node]bool)
+	report := func(n ast.Node) {
+		if !reported[n] {
+			reported[n] = true
+			config.report(pass, EndBeforeGoroutineCheck, n, "%s ended before goroutine using its context completes", sv.vr.Name())
+		}
+	}
+
+	type state struct {
+		b       *cfg.Block
+		started bool // whether a goroutine using the context may be running
+	}
+	seen := make(map[state]bool)
+
+	var walk func(b *cfg.Block, nodes []ast.Node, started bool)
+	walk = func(b *cfg.Block, nodes []ast.Node, started bool) {
+		for _, n := range nodes {
+			switch {
+			case isGoWithVar(pass.TypesInfo, n, ctx):
+				started = true
+			case started && callsWait(n):
+				started = false
+			case started && isEndCall(pass.TypesInfo, n, sv.vr):
+				report(n)
+			}
+		}
+
+		if started && len(b.Succs) == 0 && deferredEnd != nil {
+			report(deferredEnd)
+		}
+
+		for _, succ := range b.Succs {
+			if s := (state{succ, started}); !seen[s] {
+				seen[s] = true
+				walk(succ, succ.Nodes, started)
+			}
+		}
+	}
+	walk(defBlock, rest, false)
+}
+
+// getContextVar returns the context variable assigned by a span's defining statement, eg
+// ctx in `ctx, span := tracer.Start(ctx, "op")`.
+func getContextVar(info *types.Info, stmt ast.Node) *types.Var {
+	var id *ast.Ident
+	switch stmt := stmt.(type) {
+	case *ast.ValueSpec:
+		if len(stmt.Names) > 1 {
+			id = stmt.Names[0]
+		}
+	case *ast.AssignStmt:
+		if len(stmt.Lhs) > 1 {
+			id, _ = stmt.Lhs[0].(*ast.Ident)
+		}
+	}
+
+	if id == nil || id.Name == "_" {
+		return nil
+	}
+
+	v, _ := info.ObjectOf(id).(*types.Var)
+	return v
+}
+
+// getDeferredEnd returns the defer statement that ends the span v, either directly
+// or from a deferred function literal.
+func getDeferredEnd(info *types.Info, node ast.Node, v *types.Var) *ast.DeferStmt {
+	var deferred *ast.DeferStmt
+	ast.Inspect(node, func(n ast.Node) bool {
+		d, ok := n.(*ast.DeferStmt)
+		if !ok || deferred != nil {
+			return deferred == nil
+		}
+
+		ast.Inspect(d.Call, func(n ast.Node) bool {
+			if isEndSelector(info, n, v) {
+				deferred = d
+			}
+			return deferred == nil
+		})
+		return deferred == nil
+	})
+	return deferred
+}
+
+// isGoWithVar reports whether n is a go statement that references v.
+func isGoWithVar(info *types.Info, n ast.Node, v *types.Var) bool {
+	goStmt, ok := n.(*ast.GoStmt)
+	if !ok {
+		return false
+	}
+
+	found := false
+	ast.Inspect(goStmt.Call, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// callsWait reports whether n calls a Wait method, eg sync.WaitGroup.Wait.
+func callsWait(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Wait" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isEndCall reports whether n is a statement that calls End on the span v.
+func isEndCall(info *types.Info, n ast.Node, v *types.Var) bool {
+	stmt, ok := n.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+
+	call, ok := stmt.X.(*ast.CallExpr)
+	return ok && isEndSelector(info, call.Fun, v)
+}
+
+// isEndSelector reports whether n is the selector v.End.
+func isEndSelector(info *types.Info, n ast.Node, v *types.Var) bool {
+	sel, ok := n.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "End" {
+		return false
+	}
+
+	id, ok := ast.Unparen(sel.X).(*ast.Ident)
+	return ok && info.Uses[id] == v
+}
@@ -0,0 +1,132 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportUnpropagatedRequestContext reports an HTTP request being passed on, eg to the next
+// handler of a middleware, without the context of a span that was started from the request's
+// context. Downstream handlers would not see the span.
+func reportUnpropagatedRequestContext(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar) {
+	// The span's context may be discarded, eg `_, span := tracer.Start(r.Context(), "op")`.
+	ctx := getContextVar(pass.TypesInfo, sv.stmt)
+	ctxName := "ctx"
+	if ctx != nil {
+		ctxName = ctx.Name()
+	}
+
+	req := getStartRequest(pass.TypesInfo, sv.stmt)
+	if req == nil {
+		return
+	}
+
+	isReq := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == req
+	}
+
+	// Only requests passed on after the span starts, and not after the span's context is
+	// propagated on every path to them, are reported.
+	var passedOn, propagated []ast.Expr
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Pos() < sv.stmt.End() {
+			return true
+		}
+
+		// r.WithContext(ctx) or r.Clone(ctx) propagates the span's context.
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isReq(sel.X) && (sel.Sel.Name == "WithContext" || sel.Sel.Name == "Clone") {
+			for _, arg := range call.Args {
+				if ctx != nil && usesVar(pass.TypesInfo, arg, ctx) {
+					propagated = append(propagated, call)
+				}
+			}
+		}
+
+		for _, arg := range call.Args {
+			if isReq(arg) {
+				passedOn = append(passedOn, arg)
+			}
+		}
+		return true
+	})
+
+	for _, arg := range passedOn {
+		if !slices.ContainsFunc(propagated, func(call ast.Expr) bool { return dominates(node, call, arg) }) {
+			config.report(pass, RequestContextCheck, arg, "request is passed on without the context of %s, use %s.WithContext(%s)", sv.vr.Name(), req.Name(), ctxName)
+		}
+	}
+}
+
+// getStartRequest returns the *http.Request variable r if the span defined by stmt is
+// started from its context, eg `ctx, span := tracer.Start(r.Context(), "op")`.
+func getStartRequest(info *types.Info, stmt ast.Node) *types.Var {
+	var rhs []ast.Expr
+	switch stmt := stmt.(type) {
+	case *ast.ValueSpec:
+		rhs = stmt.Values
+	case *ast.AssignStmt:
+		rhs = stmt.Rhs
+	}
+	if len(rhs) != 1 {
+		return nil
+	}
+
+	start, ok := ast.Unparen(rhs[0]).(*ast.CallExpr)
+	if !ok || len(start.Args) == 0 {
+		return nil
+	}
+
+	ctxCall, ok := ast.Unparen(start.Args[0]).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	sel, ok := ctxCall.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Context" {
+		return nil
+	}
+
+	id, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok || !isHTTPRequest(v.Type()) {
+		return nil
+	}
+	return v
+}
+
+// isHTTPRequest reports whether t is *net/http.Request.
+func isHTTPRequest(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "net/http" && obj.Name() == "Request"
+}
+
+// usesVar reports whether n references v.
+func usesVar(info *types.Info, n ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
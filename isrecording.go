@@ -0,0 +1,158 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultCheapAttributeFuncs are signatures of functions that are cheap enough to call
+// when computing span attributes and events, even if the span is not recording.
+var defaultCheapAttributeFuncs = []string{
+	`go.opentelemetry.io/otel/attribute\.`,
+	`go.opentelemetry.io/otel/trace\.With`,
+	`\)\.(Error|String)\(\)`,
+}
+
+// recordingOperations are the span methods whose arguments are computed even when the span
+// is not recording.
+var recordingOperations = map[string]struct{}{
+	"AddEvent":      {},
+	"SetAttributes": {},
+}
+
+// reportUnguardedAttributes reports span.SetAttributes() and span.AddEvent() calls whose
+// arguments call functions that do not match cheapFuncs, and that are not guarded by
+// span.IsRecording(). The arguments are computed even when the span is not recording,
+// eg when tracing is disabled or the span is not sampled.
+func reportUnguardedAttributes(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	reported := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		if reported[sv.vr] || sv.spanType != spanOpenTelemetry {
+			continue // IsRecording only exists in OpenTelemetry
+		}
+		reported[sv.vr] = true
+
+		isSpan := func(x ast.Expr) bool {
+			id, ok := ast.Unparen(x).(*ast.Ident)
+			return ok && (pass.TypesInfo.Uses[id] == sv.vr || sv.isAlias(pass.TypesInfo, id))
+		}
+
+		// `if !span.IsRecording() { return }` on every path to the call.
+		var guards []ast.Node
+		ast.Inspect(node, func(n ast.Node) bool {
+			stmt, ok := n.(*ast.IfStmt)
+			if !ok || len(stmt.Body.List) == 0 {
+				return true
+			}
+			if _, ok := stmt.Body.List[len(stmt.Body.List)-1].(*ast.ReturnStmt); !ok {
+				return true
+			}
+			if unary, ok := ast.Unparen(stmt.Cond).(*ast.UnaryExpr); ok && unary.Op == token.NOT && isRecordingCall(unary.X, isSpan) {
+				guards = append(guards, stmt)
+			}
+			return true
+		})
+
+		stack := make([]ast.Node, 0, stackLen)
+		ast.Inspect(node, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1] // pop
+				return true
+			}
+			stack = append(stack, n) // push
+
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !isSpan(sel.X) {
+				return true
+			}
+			if _, ok := recordingOperations[sel.Sel.Name]; !ok {
+				return true
+			}
+
+			expensive := getExpensiveCall(pass.TypesInfo, call.Args, config.cheapAttributeFuncs)
+			if expensive == nil || isGuardedByRecording(stack, isSpan) {
+				return true
+			}
+			for _, guard := range guards {
+				if dominates(node, guard, call) {
+					return true
+				}
+			}
+
+			config.report(pass, IsRecordingCheck, call, "%s.%s computes its arguments with a call to %s, guard it with %s.IsRecording()",
+				types.ExprString(sel.X), sel.Sel.Name, calleeName(pass.TypesInfo, expensive), types.ExprString(sel.X))
+			return true
+		})
+	}
+}
+
+// getExpensiveCall returns the first call in args to a function that does not match
+// cheapFuncs, or nil if there is none. Conversions and builtins are cheap.
+func getExpensiveCall(info *types.Info, args []ast.Expr, cheapFuncs *regexp.Regexp) *ast.CallExpr {
+	var expensive *ast.CallExpr
+	for _, arg := range args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok || expensive != nil {
+				return false
+			}
+
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := calleeObject(info, call).(*types.Func)
+			if !ok || cheapFuncs != nil && cheapFuncs.MatchString(fn.String()) {
+				return true
+			}
+
+			expensive = call
+			return false
+		})
+	}
+	return expensive
+}
+
+// isGuardedByRecording reports whether the top of stack is in the body of an if statement
+// whose condition checks that the span is recording, eg `if span.IsRecording() { ... }`.
+func isGuardedByRecording(stack []ast.Node, isSpan func(ast.Expr) bool) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		stmt, ok := stack[i].(*ast.IfStmt)
+		if !ok || stack[i+1] != stmt.Body {
+			continue
+		}
+
+		guarded := false
+		ast.Inspect(stmt.Cond, func(n ast.Node) bool {
+			if unary, ok := n.(*ast.UnaryExpr); ok && unary.Op == token.NOT {
+				return false
+			}
+			if x, ok := n.(ast.Expr); ok && isRecordingCall(x, isSpan) {
+				guarded = true
+			}
+			return !guarded
+		})
+		if guarded {
+			return true
+		}
+	}
+	return false
+}
+
+// isRecordingCall reports whether x is a call to IsRecording on a span, eg span.IsRecording().
+func isRecordingCall(x ast.Expr, isSpan func(ast.Expr) bool) bool {
+	call, ok := ast.Unparen(x).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "IsRecording" && isSpan(sel.X)
+}
@@ -0,0 +1,98 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportLateSpan reports the first call whose error is checked before the first span of a
+// function returning an error is started, eg `if err := validate(req); err != nil`. The
+// failures of work done before the span starts are not recorded on it, so the span should
+// wrap the whole operation. Calls whose errors are dropped, deferred or run in a goroutine
+// are not reported.
+func reportLateSpan(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	if len(spanVars) == 0 || !returnsError(pass.TypesInfo, node) {
+		return
+	}
+
+	start := token.NoPos
+	for _, sv := range spanVars {
+		if start == token.NoPos || sv.stmt.Pos() < start {
+			start = sv.stmt.Pos()
+		}
+	}
+
+	var first *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if first != nil || n == nil || n.Pos() >= start {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n == node // don't stray into nested functions
+		case *ast.ExprStmt, *ast.DeferStmt, *ast.GoStmt:
+			return false // the error is not checked
+		case *ast.AssignStmt:
+			return !isBlankAssign(n)
+		case *ast.CallExpr:
+			if callReturnsError(pass.TypesInfo, n) {
+				first = n
+				return false
+			}
+		}
+		return true
+	})
+
+	if first != nil {
+		config.report(pass, LateSpanCheck, first, "operation error occurs before span starts; failures won't be traced")
+	}
+}
+
+// returnsError reports whether the function node has an error result.
+func returnsError(info *types.Info, node ast.Node) bool {
+	var sig *types.Signature
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		if obj := info.Defs[node.Name]; obj != nil {
+			sig, _ = obj.Type().(*types.Signature)
+		}
+	case *ast.FuncLit:
+		sig, _ = info.TypeOf(node).(*types.Signature)
+	}
+	if sig == nil {
+		return false
+	}
+
+	for i := 0; i < sig.Results().Len(); i++ {
+		if isErrorType(sig.Results().At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// callReturnsError reports whether call is a function call with an error result, rather
+// than a conversion.
+func callReturnsError(info *types.Info, call *ast.CallExpr) bool {
+	if tv, ok := info.Types[call.Fun]; !ok || tv.IsType() {
+		return false
+	}
+
+	switch t := info.TypeOf(call).(type) {
+	case nil:
+		return false
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if isErrorType(t.At(i).Type()) {
+				return true
+			}
+		}
+		return false
+	default:
+		return isErrorType(t)
+	}
+}
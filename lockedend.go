@@ -0,0 +1,122 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// reportLockedEnd reports calls to span.End() that can be reached while a sync.Mutex or
+// sync.RWMutex is locked, between mu.Lock() and mu.Unlock(). Ending a span can block on the
+// exporter, which holds up everything else waiting on the lock. A deferred span.End() is
+// only reported if it runs before a deferred mu.Unlock(), ie it is deferred after it.
+func reportLockedEnd(pass *analysis.Pass, config *Config, g *cfg.CFG, spanVars map[*ast.Ident]spanVar) {
+	if g == nil {
+		return
+	}
+
+	vars := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		vars[sv.vr] = true
+	}
+
+	reported := make(map[ast.Node]bool)
+	for _, b := range g.Blocks {
+		for i, n := range b.Nodes {
+			mu, ok := getLockCall(pass.TypesInfo, n, "Lock", "RLock")
+			if !ok {
+				continue
+			}
+
+			for _, end := range getLockedEnds(pass.TypesInfo, b, b.Nodes[i+1:], mu, vars) {
+				if !reported[end.node] {
+					reported[end.node] = true
+					config.report(pass, LockedEndCheck, end.node, "%s.End is called while %s is locked, span operation inside locked section may block on exporter", end.v.Name(), mu)
+				}
+			}
+		}
+	}
+}
+
+// lockedEnd is a call ending the span v, reached while a mutex is locked.
+type lockedEnd struct {
+	node ast.Node
+	v    *types.Var
+}
+
+// getLockedEnds returns the calls ending one of vars that can be reached from rest, the
+// statements of b after mu is locked. The search stops where mu is unlocked. After a deferred
+// mu.Unlock(), mu stays locked until the function returns, and deferred span.End() calls run
+// before it.
+func getLockedEnds(info *types.Info, b *cfg.Block, rest []ast.Node, mu string, vars map[*types.Var]bool) []lockedEnd {
+	type state struct {
+		b        *cfg.Block
+		deferred bool // mu.Unlock() is deferred
+	}
+
+	var ends []lockedEnd
+	seen := make(map[state]bool)
+
+	var search func(b *cfg.Block, nodes []ast.Node, deferred bool)
+	search = func(b *cfg.Block, nodes []ast.Node, deferred bool) {
+		for _, n := range nodes {
+			if name, ok := getLockCall(info, n, "Unlock", "RUnlock"); ok && name == mu {
+				return
+			}
+			if stmt, ok := n.(*ast.DeferStmt); ok {
+				if name, ok := getLockCall(info, &ast.ExprStmt{X: stmt.Call}, "Unlock", "RUnlock"); ok && name == mu {
+					deferred = true
+					continue
+				}
+			}
+
+			for v := range vars {
+				if isEndCall(info, n, v) {
+					ends = append(ends, lockedEnd{node: n, v: v})
+				} else if stmt, ok := n.(*ast.DeferStmt); ok && deferred && deferEnds(info, stmt, v) {
+					ends = append(ends, lockedEnd{node: n, v: v})
+				}
+			}
+		}
+
+		for _, succ := range b.Succs {
+			if s := (state{succ, deferred}); !seen[s] {
+				seen[s] = true
+				search(succ, succ.Nodes, deferred)
+			}
+		}
+	}
+	search(b, rest, false)
+
+	return ends
+}
+
+// getLockCall returns the mutex locked or unlocked by n, if n is a call like mu.Lock() to one
+// of the sync package's methods named names.
+func getLockCall(info *types.Info, n ast.Node, names ...string) (string, bool) {
+	stmt, ok := n.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync" {
+		return "", false
+	}
+	for _, name := range names {
+		if fn.Name() == name {
+			return types.ExprString(sel.X), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package spancheck
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportLoopSpans reports spans started in the body of at least LoopSpanDepth nested loops,
+// which start a span every iteration and can overwhelm the collector. Spans started under a
+// condition in the innermost loop, eg `if i%100 == 0`, are assumed to be sampled.
+func reportLoopSpans(pass *analysis.Pass, config *Config, node ast.Node) {
+	depth := config.LoopSpanDepth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	stack := make([]ast.Node, 0, stackLen)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			if len(stack) > 0 {
+				return false // don't stray into nested functions
+			}
+		case nil:
+			stack = stack[:len(stack)-1] // pop
+			return true
+		}
+		stack = append(stack, n) // push
+
+		i := getCallIndex(stack)
+		if _, isStart := isSpanStart(pass.TypesInfo, n, config.startSpanMatchers); !isStart || i < 0 {
+			return true
+		}
+		if config.isIgnoredSpanName(getSpanName(pass.TypesInfo, stack[i])) {
+			return true
+		}
+
+		if loops, guarded := getLoopDepth(stack); loops >= depth && !guarded {
+			config.report(pass, LoopSpanCheck, stack[i], "span started unconditionally in loop may cause high cardinality/volume, consider sampling or batching")
+		}
+		return true
+	})
+}
+
+// getLoopDepth returns the number of loops whose body encloses the last node of stack, and
+// whether it is in the body of a conditional statement inside the innermost of them.
+func getLoopDepth(stack []ast.Node) (loops int, guarded bool) {
+	for i := 0; i < len(stack)-1; i++ {
+		switch n := stack[i].(type) {
+		case *ast.ForStmt:
+			if stack[i+1] == n.Body {
+				loops, guarded = loops+1, false
+			}
+		case *ast.RangeStmt:
+			if stack[i+1] == n.Body {
+				loops, guarded = loops+1, false
+			}
+		case *ast.IfStmt:
+			if loops > 0 && (stack[i+1] == n.Body || stack[i+1] == n.Else) {
+				guarded = true
+			}
+		case *ast.CaseClause, *ast.CommClause:
+			if loops > 0 {
+				guarded = true
+			}
+		}
+	}
+	return loops, guarded
+}
@@ -0,0 +1,37 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportMissingSpan reports a function declaration that takes a context.Context as its first
+// parameter, has a full name matching config.mustHaveSpanFuncs if set, and never starts a span.
+func reportMissingSpan(pass *analysis.Pass, config *Config, decl *ast.FuncDecl) {
+	if decl.Body == nil {
+		return
+	}
+
+	params := decl.Type.Params.List
+	if len(params) == 0 || !isContext(pass.TypesInfo.TypeOf(params[0].Type)) {
+		return
+	}
+
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok || config.mustHaveSpanFuncs != nil && !config.mustHaveSpanFuncs.MatchString(fn.FullName()) {
+		return
+	}
+
+	started := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if _, ok := isSpanStart(pass.TypesInfo, n, config.startSpanMatchers); ok {
+			started = true
+		}
+		return !started
+	})
+	if !started {
+		config.report(pass, MustHaveSpanCheck, decl.Name, "%s takes a context.Context but does not start a span", decl.Name.Name)
+	}
+}
@@ -0,0 +1,191 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportNilTracers reports spans started with a tracer that may be nil, and is not checked
+// against nil first. Tracers in struct fields, package-level variables and parameters may be
+// nil, eg when observability is optional. Tracers from calls, like otel.Tracer("foo"), and
+// local variables are assumed to be set.
+func reportNilTracers(pass *analysis.Pass, config *Config, node ast.Node) {
+	params := getParams(pass.TypesInfo, node)
+
+	// `if tracer == nil` before the span is started, whose body returns early or sets a
+	// default tracer.
+	nilChecks := make(map[string][]token.Pos)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if stmt, ok := n.(*ast.IfStmt); ok {
+			for _, x := range getNilComparisons(stmt.Cond, token.EQL) {
+				if isNilGuard(stmt.Body, x) {
+					nilChecks[x] = append(nilChecks[x], stmt.Pos())
+				}
+			}
+		}
+		return true
+	})
+
+	stack := make([]ast.Node, 0, stackLen)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			if len(stack) > 0 {
+				return false // don't stray into nested functions
+			}
+		case nil:
+			stack = stack[:len(stack)-1] // pop
+			return true
+		}
+		stack = append(stack, n) // push
+
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if _, isStart := isSpanStart(pass.TypesInfo, sel, config.startSpanMatchers); !isStart {
+			return true
+		}
+		if s, ok := pass.TypesInfo.Selections[sel]; !ok || s.Kind() != types.MethodVal {
+			return true // not a method of a tracer
+		}
+
+		tracer := ast.Unparen(sel.X)
+		if !isNilable(pass.TypesInfo, tracer, params) {
+			return true
+		}
+
+		name := types.ExprString(tracer)
+		for _, pos := range nilChecks[name] {
+			if pos < sel.Pos() {
+				return true
+			}
+		}
+		if isGuardedByNilCheck(stack, name) {
+			return true
+		}
+
+		config.report(pass, NilTracerCheck, sel, "%s may be nil, check it before calling %s", name, sel.Sel.Name)
+		return true
+	})
+}
+
+// isNilable reports whether the tracer expression x can be nil and comes from a struct field,
+// a package-level variable or one of params.
+func isNilable(info *types.Info, x ast.Expr, params map[*types.Var]bool) bool {
+	switch info.TypeOf(x).Underlying().(type) {
+	case *types.Interface, *types.Pointer:
+	default:
+		return false
+	}
+
+	switch x := x.(type) {
+	case *ast.Ident:
+		v, ok := info.Uses[x].(*types.Var)
+		return ok && (params[v] || v.Pkg() != nil && v.Parent() == v.Pkg().Scope())
+	case *ast.SelectorExpr:
+		if s, ok := info.Selections[x]; ok {
+			return s.Kind() == types.FieldVal
+		}
+		v, ok := info.Uses[x.Sel].(*types.Var)
+		return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+	}
+	return false
+}
+
+// getParams returns the receiver and parameters of the function node.
+func getParams(info *types.Info, node ast.Node) map[*types.Var]bool {
+	var fields []*ast.Field
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		if node.Recv != nil {
+			fields = append(fields, node.Recv.List...)
+		}
+		fields = append(fields, node.Type.Params.List...)
+	case *ast.FuncLit:
+		fields = append(fields, node.Type.Params.List...)
+	}
+
+	params := make(map[*types.Var]bool)
+	for _, field := range fields {
+		for _, name := range field.Names {
+			if v, ok := info.Defs[name].(*types.Var); ok {
+				params[v] = true
+			}
+		}
+	}
+	return params
+}
+
+// isGuardedByNilCheck reports whether the top of stack is in the body of an if statement
+// whose condition checks that name is not nil, eg `if tracer != nil { ... }`.
+func isGuardedByNilCheck(stack []ast.Node, name string) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		stmt, ok := stack[i].(*ast.IfStmt)
+		if !ok || stack[i+1] != stmt.Body {
+			continue
+		}
+
+		for _, x := range getNilComparisons(stmt.Cond, token.NEQ) {
+			if x == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNilGuard reports whether body, of an if statement checking that name is nil, guards
+// later uses of name: it returns, panics or assigns name, eg `tracer = otel.Tracer("foo")`.
+func isNilGuard(body *ast.BlockStmt, name string) bool {
+	guards := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // returns from nested functions do not return from this one
+		case *ast.ReturnStmt:
+			guards = true
+		case *ast.CallExpr:
+			if id, ok := ast.Unparen(n.Fun).(*ast.Ident); ok && id.Name == "panic" {
+				guards = true
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if types.ExprString(ast.Unparen(lhs)) == name {
+					guards = true
+				}
+			}
+		}
+		return !guards
+	})
+	return guards
+}
+
+// getNilComparisons returns the expressions compared to nil with op in cond, including in
+// both sides of && and ||, eg tracer in `tracer != nil && ok`.
+func getNilComparisons(cond ast.Expr, op token.Token) []string {
+	var xs []string
+	ast.Inspect(cond, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok || bin.Op != op {
+			return true
+		}
+
+		if isNilIdent(bin.Y) {
+			xs = append(xs, types.ExprString(ast.Unparen(bin.X)))
+		} else if isNilIdent(bin.X) {
+			xs = append(xs, types.ExprString(ast.Unparen(bin.Y)))
+		}
+		return true
+	})
+	return xs
+}
+
+// isNilIdent reports whether x is the identifier nil.
+func isNilIdent(x ast.Expr) bool {
+	id, ok := ast.Unparen(x).(*ast.Ident)
+	return ok && id.Name == "nil"
+}
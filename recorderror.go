@@ -0,0 +1,158 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportRecordedErrorMismatch reports span.RecordError(err) calls followed, in the same block,
+// by a return of a different error variable. This is usually a copy-paste bug recording the
+// wrong error. Only local variables are compared, since returning a package level sentinel
+// error in place of the recorded one is common, and a returned variable built from the recorded
+// error, eg by wrapping it, is not reported.
+func reportRecordedErrorMismatch(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	vars := make(map[*types.Var]spanVar)
+	for _, sv := range spanVars {
+		if sv.spanType == spanOpenTelemetry { // RecordError only exists in OpenTelemetry
+			vars[sv.vr] = sv
+		}
+	}
+	if len(vars) == 0 {
+		return
+	}
+
+	inspectFunc(node, func(n ast.Node) {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return
+		}
+
+		for i, stmt := range block.List {
+			recorded := getRecordedError(pass.TypesInfo, stmt, vars)
+			if recorded == nil {
+				continue
+			}
+
+			for _, next := range block.List[i+1:] {
+				ret, ok := next.(*ast.ReturnStmt)
+				if !ok {
+					continue
+				}
+				returned := getReturnedErrorVar(pass.TypesInfo, ret)
+				if returned != nil && returned != pass.TypesInfo.Uses[recorded] && !isBuiltFrom(pass.TypesInfo, node, returned, pass.TypesInfo.Uses[recorded]) {
+					config.report(pass, RecordErrorMismatchCheck, recorded, "RecordError records a different error than the one returned on this path")
+				}
+				break
+			}
+		}
+	})
+}
+
+// getRecordedError returns the local error variable passed to RecordError on one of vars, if
+// stmt is a call like span.RecordError(err).
+func getRecordedError(info *types.Info, stmt ast.Stmt, vars map[*types.Var]spanVar) *ast.Ident {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "RecordError" {
+		return nil
+	}
+	id, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if v, ok := info.Uses[id].(*types.Var); !ok || !isSpanOf(info, id, v, vars) {
+		return nil
+	}
+
+	arg, ok := ast.Unparen(call.Args[0]).(*ast.Ident)
+	if !ok || !isLocalError(info, arg) {
+		return nil
+	}
+	return arg
+}
+
+// isSpanOf reports whether id, which uses v, refers to one of vars or their aliases.
+func isSpanOf(info *types.Info, id *ast.Ident, v *types.Var, vars map[*types.Var]spanVar) bool {
+	if _, ok := vars[v]; ok {
+		return true
+	}
+	for _, sv := range vars {
+		if sv.isAlias(info, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// getReturnedErrorVar returns the local error variable returned by ret, if any.
+func getReturnedErrorVar(info *types.Info, ret *ast.ReturnStmt) types.Object {
+	for _, r := range ret.Results {
+		if id, ok := ast.Unparen(r).(*ast.Ident); ok && isLocalError(info, id) {
+			return info.Uses[id]
+		}
+	}
+	return nil
+}
+
+// isLocalError reports whether id refers to an error variable declared in a function.
+func isLocalError(info *types.Info, id *ast.Ident) bool {
+	v, ok := info.Uses[id].(*types.Var)
+	return ok && v.Pkg() != nil && v.Parent() != v.Pkg().Scope() && isErrorType(v.Type())
+}
+
+// isBuiltFrom reports whether v is assigned, in node, a value whose expression uses from, eg
+// wrapped := fmt.Errorf("op: %w", err) for v wrapped and from err.
+func isBuiltFrom(info *types.Info, node ast.Node, v, from types.Object) bool {
+	uses := func(e ast.Expr) bool {
+		found := false
+		ast.Inspect(e, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && info.Uses[id] == from {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+	isV := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && (info.Defs[id] == v || info.Uses[id] == v)
+	}
+
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		var lhs, rhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			lhs, rhs = n.Lhs, n.Rhs
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				lhs = append(lhs, name)
+			}
+			rhs = n.Values
+		default:
+			return !found
+		}
+
+		for i, l := range lhs {
+			if !isV(l) {
+				continue
+			}
+			if len(rhs) == len(lhs) {
+				found = found || uses(rhs[i])
+			} else if len(rhs) == 1 { // eg v, ok := wrap(err)
+				found = found || uses(rhs[0])
+			}
+		}
+		return !found
+	})
+	return found
+}
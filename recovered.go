@@ -0,0 +1,123 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportRecoveredErrors reports deferred functions that recover a panic and return it as an
+// error, by assigning a named error result, without calling one of selNames on the span. The
+// error is returned by the defer rather than a return statement, so getMissingSpanCalls cannot
+// see it. Only defers registered after the span is started are checked.
+func reportRecoveredErrors(pass *analysis.Pass, config *Config, check Check, node ast.Node, sv spanVar, selNames []string, selName string) {
+	results := getErrorResults(pass.TypesInfo, node)
+	if len(results) == 0 {
+		return
+	}
+
+	inspectFunc(node, func(n ast.Node) {
+		stmt, ok := n.(*ast.DeferStmt)
+		if !ok || stmt.Pos() < sv.stmt.End() {
+			return
+		}
+		lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+		if !ok || !callsRecover(pass.TypesInfo, lit) || callsSpan(pass.TypesInfo, lit, sv, selNames) {
+			return
+		}
+
+		for _, assign := range getResultAssigns(pass.TypesInfo, lit, results) {
+			config.report(pass, check, assign, "%s.%s is not called when a recovered panic is returned as an error", sv.vr.Name(), selName)
+		}
+	})
+}
+
+// getErrorResults returns the named results of the function node that are errors.
+func getErrorResults(info *types.Info, node ast.Node) map[*types.Var]bool {
+	var ft *ast.FuncType
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		ft = node.Type
+	case *ast.FuncLit:
+		ft = node.Type
+	}
+	if ft == nil || ft.Results == nil {
+		return nil
+	}
+
+	results := make(map[*types.Var]bool)
+	for _, field := range ft.Results.List {
+		for _, name := range field.Names {
+			if v, ok := info.Defs[name].(*types.Var); ok && isErrorType(v.Type()) {
+				results[v] = true
+			}
+		}
+	}
+	return results
+}
+
+// callsRecover reports whether the function literal calls the recover builtin.
+func callsRecover(info *types.Info, lit *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok || found {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok && info.Uses[id] == types.Universe.Lookup("recover") {
+				found = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// callsSpan reports whether the function literal calls one of selNames on the span.
+func callsSpan(info *types.Info, lit *ast.FuncLit, sv spanVar, selNames []string) bool {
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		id, ok := ast.Unparen(sel.X).(*ast.Ident)
+		if !ok || info.Uses[id] != sv.vr && !sv.isAlias(info, id) {
+			return true
+		}
+		for _, name := range selNames {
+			if sel.Sel.Name == name {
+				found = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// getResultAssigns returns the assignments to one of results in the function literal.
+func getResultAssigns(info *types.Info, lit *ast.FuncLit, results map[*types.Var]bool) []*ast.AssignStmt {
+	var assigns []*ast.AssignStmt
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			id, ok := ast.Unparen(lhs).(*ast.Ident)
+			if v, isVar := info.Uses[id].(*types.Var); ok && isVar && results[v] {
+				assigns = append(assigns, assign)
+				break
+			}
+		}
+		return true
+	})
+	return assigns
+}
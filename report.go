@@ -0,0 +1,93 @@
+package spancheck
+
+import (
+	"fmt"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Finding is a problem found by the analyzer.
+type Finding struct {
+	// Check is the name of the check that found the problem, eg "end".
+	Check string
+
+	// Pos and End are the range of the offending node.
+	Pos token.Pos
+	End token.Pos
+
+	// Position is the position of Pos.
+	Position token.Position
+
+	// Message describes the problem.
+	Message string
+
+	// Confidence is how likely the finding is to be a real problem, one of
+	// ConfidenceHigh, ConfidenceMedium or ConfidenceLow.
+	Confidence string
+}
+
+// Confidence levels of findings.
+const (
+	// ConfidenceHigh is the confidence of most findings, eg a span that is never ended.
+	ConfidenceHigh = "high"
+
+	// ConfidenceMedium is the confidence of a missing call on some paths of a span
+	// that has the call on other paths.
+	ConfidenceMedium = "medium"
+
+	// ConfidenceLow is the confidence of a missing call on a span that escapes the
+	// function, and so may have the call made elsewhere.
+	ConfidenceLow = "low"
+)
+
+// report reports a finding for check at rng, either to the config's ReportFunc or to the pass.
+func (c *Config) report(pass *analysis.Pass, check Check, rng analysis.Range, format string, args ...interface{}) {
+	c.reportConfidence(pass, check, ConfidenceHigh, rng, format, args...)
+}
+
+// reportConfidence is like report, for findings that may not be high confidence.
+func (c *Config) reportConfidence(pass *analysis.Pass, check Check, confidence string, rng analysis.Range, format string, args ...interface{}) {
+	c.reportFixes(pass, check, confidence, rng, nil, format, args...)
+}
+
+// reportFixes is like reportConfidence, with fixes suggested for the finding. The fixes are
+// dropped when reporting to the config's ReportFunc.
+func (c *Config) reportFixes(pass *analysis.Pass, check Check, confidence string, rng analysis.Range, fixes []analysis.SuggestedFix, format string, args ...interface{}) {
+	if !c.inDiff(pass, rng.Pos()) {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+
+	if c.ReportFunc != nil {
+		c.ReportFunc(Finding{
+			Check:      check.String(),
+			Pos:        rng.Pos(),
+			End:        rng.End(),
+			Position:   pass.Fset.Position(rng.Pos()),
+			Message:    msg,
+			Confidence: confidence,
+		})
+		return
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:            rng.Pos(),
+		End:            rng.End(),
+		Category:       check.String(),
+		Message:        msg,
+		SuggestedFixes: fixes,
+	})
+}
+
+// inDiff reports whether a finding at pos should be reported, because it is on a line changed
+// by the config's diff or there is no diff.
+func (c *Config) inDiff(pass *analysis.Pass, pos token.Pos) bool {
+	if c.diff == nil {
+		return true
+	}
+
+	position := pass.Fset.Position(pos)
+	return c.diff.contains(position.Filename, position.Line)
+}
@@ -0,0 +1,91 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// Span is a read-only view of a span started in a function, as passed to custom checks.
+type Span struct {
+	// Name is the name of the span's variable, eg "span".
+	Name string
+
+	// Type is the kind of span, one of the keys of SpanTypes, eg "opentelemetry".
+	Type string
+
+	// DefPos is the position of the span's variable where the span is started.
+	DefPos token.Pos
+
+	// Var is the span's variable.
+	Var *types.Var
+
+	// Aliases are other variables assigned the span, eg s in `s := span`, in source order.
+	Aliases []*types.Var
+
+	// Stmt is the statement that starts the span, eg `ctx, span := tracer.Start(ctx, "foo")`.
+	Stmt ast.Node
+}
+
+// CustomCheck is a check of a span started in a function. g is the control flow graph of
+// the function, or nil if it has none. It returns a diagnostic to report, or nil.
+type CustomCheck func(pass *analysis.Pass, span Span, g *cfg.CFG) *analysis.Diagnostic
+
+// customCheckName is the name of findings from custom checks without a category.
+const customCheckName = "custom"
+
+// export returns the exported view of sv.
+func (sv spanVar) export() Span {
+	aliases := make([]*types.Var, 0, len(sv.aliases))
+	for v := range sv.aliases {
+		aliases = append(aliases, v)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Pos() < aliases[j].Pos() })
+
+	return Span{
+		Name:    sv.vr.Name(),
+		Type:    sv.spanType.String(),
+		DefPos:  sv.id.Pos(),
+		Var:     sv.vr,
+		Aliases: aliases,
+		Stmt:    sv.stmt,
+	}
+}
+
+// runCustomChecks runs the config's custom checks on each span and reports their diagnostics.
+func runCustomChecks(pass *analysis.Pass, config *Config, g *cfg.CFG, spanVars map[*ast.Ident]spanVar) {
+	for _, sv := range spanVars {
+		for _, check := range config.CustomChecks {
+			d := check(pass, sv.export(), g)
+			if d == nil || !config.inDiff(pass, d.Pos) {
+				continue
+			}
+
+			if config.ReportFunc == nil {
+				pass.Report(*d)
+				continue
+			}
+
+			name := d.Category
+			if name == "" {
+				name = customCheckName
+			}
+			end := d.End
+			if end == token.NoPos {
+				end = d.Pos
+			}
+			config.ReportFunc(Finding{
+				Check:      name,
+				Pos:        d.Pos,
+				End:        end,
+				Position:   pass.Fset.Position(d.Pos),
+				Message:    d.Message,
+				Confidence: ConfidenceHigh,
+			})
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package spancheck
 
 import (
+	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/token"
 	"go/types"
 	"regexp"
+	"slices"
+	"sort"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/ctrlflow"
@@ -29,10 +35,26 @@ var SpanTypes = map[string]spanType{
 	"opencensus":    spanOpenCensus,
 }
 
+// String returns the name of the span type in SpanTypes.
+func (t spanType) String() string {
+	for name, st := range SpanTypes {
+		if st == t {
+			return name
+		}
+	}
+	return ""
+}
+
 // this approach stolen from errcheck
 // https://github.com/kisielk/errcheck/blob/7f94c385d0116ccc421fbb4709e4a484d98325ee/errcheck/errcheck.go#L22
 var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
 
+// NewAnalyzer returns a new analyzer with the default config.
+// Its config can be set with flags, eg when run with singlechecker.
+func NewAnalyzer() *analysis.Analyzer {
+	return newAnalyzer(NewDefaultConfig())
+}
+
 // NewAnalyzerWithConfig returns a new analyzer configured with the Config passed in.
 // Its config can be set for testing.
 func NewAnalyzerWithConfig(config *Config) *analysis.Analyzer {
@@ -40,7 +62,9 @@ func NewAnalyzerWithConfig(config *Config) *analysis.Analyzer {
 }
 
 func newAnalyzer(config *Config) *analysis.Analyzer {
-	config.finalize()
+	// Analyzers created from the same config share its flag set, which can only have each
+	// flag registered once.
+	config.registerFlagsOnce.Do(config.registerFlags)
 
 	return &analysis.Analyzer{
 		Name:  "spancheck",
@@ -56,16 +80,78 @@ func newAnalyzer(config *Config) *analysis.Analyzer {
 
 func run(config *Config) func(*analysis.Pass) (interface{}, error) {
 	return func(pass *analysis.Pass) (interface{}, error) {
+		// Flags are parsed after the analyzer is created, so finalize on the first run.
+		config.finalizeOnce.Do(config.finalize)
+		if config.diffErr != nil {
+			return nil, config.diffErr
+		}
+		if config.noChecksEnabled && len(config.CustomChecks) == 0 {
+			return nil, nil // nothing to report
+		}
+		if config.excludePkgs != nil && config.excludePkgs.MatchString(pass.Pkg.Path()) {
+			return nil, nil // excluded package
+		}
+
 		inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
 		nodeFilter := []ast.Node{
 			(*ast.FuncLit)(nil),  // f := func() {}
 			(*ast.FuncDecl)(nil), // func foo() {}
 		}
-		inspect.Preorder(nodeFilter, func(n ast.Node) {
-			runFunc(pass, n, config)
+		// Only functions reachable from entry points are checked, if any are set.
+		var reachable map[*ast.FuncDecl]bool
+		if config.entryPoints != nil {
+			reachable = getReachableFuncs(pass, config.entryPoints)
+		}
+
+		ignoredFiles := getIgnoredFiles(pass.Files, config.IgnoreBuildTags)
+
+		var ends []endCall
+		inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+			if !push {
+				return true
+			}
+
+			if file, ok := stack[0].(*ast.File); ok && ignoredFiles[file] {
+				return false // file requires an ignored build tag
+			}
+
+			if reachable != nil {
+				if decl, ok := stack[1].(*ast.FuncDecl); !ok || !reachable[decl] {
+					return true
+				}
+			}
+
+			// Checks can be disabled by a directive on the enclosing function declaration.
+			var disabled map[Check]bool
+			if len(stack) > 1 {
+				if decl, ok := stack[1].(*ast.FuncDecl); ok {
+					disabled = getDisabledChecks(decl.Doc)
+				}
+			}
+			if len(disabled) == len(Checks) {
+				return true // all checks are disabled
+			}
+
+			ends = append(ends, runFunc(pass, n, config, disabled)...)
+			return true
 		})
 
+		if config.endStyleEnabled {
+			// Check if spans are ended in a consistent style within each file.
+			reportInconsistentEndStyle(pass, config, ends)
+		}
+
+		if config.tracerNameEnabled {
+			// Check if tracers are named for their package.
+			reportTracerNames(pass, config)
+		}
+
+		if config.spanHelperEnabled {
+			// Check if spans are annotated directly outside of the helper packages.
+			reportDirectSpanCalls(pass, config)
+		}
+
 		return nil, nil
 	}
 }
@@ -75,146 +161,1077 @@ type spanVar struct {
 	id       *ast.Ident
 	vr       *types.Var
 	spanType spanType
+
+	// name is the span's name passed to the call starting it, eg "fetch-user", if it is a
+	// constant.
+	name string
+
+	// aliases are other variables assigned the span, eg s in `s := span`.
+	aliases map[*types.Var]bool
+
+	// refs are variables that hold the span behind a pointer or in a slice, array or map,
+	// eg p in `p := &span` or spans in `spans := []trace.Span{span}`. Calls like
+	// `(*p).End()` and `spans[0].End()` count as calls on the span.
+	refs map[*types.Var]bool
+
+	// endCalls are calls to end functions that are passed the span, eg FinishSpan(ctx, span),
+	// appends of the span to a slice whose spans are ended in a range loop, and calls to
+	// variables bound to the span's End method, eg end() after `end := span.End`.
+	endCalls map[*ast.CallExpr]bool
+
+	// deferredEndCalls are deferred calls to deferred end functions that are passed the span,
+	// eg `defer finishSpan(span, &err)`, including calls in deferred function literals, eg
+	// `defer func() { finishSpan(span, &err) }()`. They map to whether the call is also passed
+	// a pointer to an error, which lets it set the span's status and record the error.
+	deferredEndCalls map[*ast.CallExpr]bool
+
+	// endValues are the span.End method values bound to variables, eg in `end := span.End`,
+	// which do not end the span until they are called.
+	endValues map[*ast.SelectorExpr]bool
+
+	// returns are return statements that return the span to the caller, which ends it,
+	// eg `return ctx, span`. They are only set if the span can be ended elsewhere.
+	returns map[*ast.ReturnStmt]bool
+
+	// noEnd is whether the span's line has a noend directive, so it is not expected to be
+	// ended in the function.
+	noEnd bool
+}
+
+// runFunc checks if the node is a function, has a span, and the span never has SetStatus set.
+// It returns the calls ending the function's spans, for checks across the file.
+func runFunc(pass *analysis.Pass, node ast.Node, config *Config, disabled map[Check]bool) []endCall {
+	// copying https://cs.opensource.google/go/x/tools/+/master:go/analysis/passes/lostcancel/lostcancel.go
+
+	// Find scope of function node
+	var funcScope *types.Scope
+	switch v := node.(type) {
+	case *ast.FuncLit:
+		funcScope = pass.TypesInfo.Scopes[v.Type]
+	case *ast.FuncDecl:
+		funcScope = pass.TypesInfo.Scopes[v.Type]
+		fnSig := pass.TypesInfo.ObjectOf(v.Name).String()
+
+		// Skip checking spans in this function if it's a custom starter/creator.
+		if config.startSpanMatchersCustomRegex != nil && config.startSpanMatchersCustomRegex.MatchString(fnSig) {
+			return nil
+		}
+	}
+
+	// Maps each span variable to its defining ValueSpec/AssignStmt.
+	spanVars := make(map[*ast.Ident]spanVar)
+
+	// Find the set of span vars to analyze.
+	stack := make([]ast.Node, 0, stackLen)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			if len(stack) > 0 {
+				return false // don't stray into nested functions
+			}
+		case nil:
+			stack = stack[:len(stack)-1] // pop
+			return true
+		}
+		stack = append(stack, n) // push
+
+		// Look for [{AssignStmt,ValueSpec} CallExpr SelectorExpr]:
+		//
+		//   ctx, span     := otel.Tracer("app").Start(...)
+		//   ctx, span     = otel.Tracer("app").Start(...)
+		//   var ctx, span = otel.Tracer("app").Start(...)
+		sType, isStart := isSpanStart(pass.TypesInfo, n, config.startSpanMatchers)
+		if !isStart {
+			return true
+		}
+
+		i := getCallIndex(stack)
+		if i < 0 {
+			return true
+		}
+
+		name := getSpanName(pass.TypesInfo, stack[i])
+		if config.isIgnoredSpanName(name) {
+			return true // all checks of the span are skipped
+		}
+
+		stmt := getStartStmt(pass.TypesInfo, stack[:i])
+		if _, ok := stmt.(*ast.DeferStmt); ok {
+			if !disabled[EndCheck] {
+				config.report(pass, EndCheck, n, "span started in defer is immediately discarded")
+			}
+			return true
+		}
+
+		id := getID(stmt)
+		if lhs := getSpanExpr(stmt); id == nil && lhs != nil {
+			// The span is stored in a field or element, eg `s.ctx, s.span = tracer.Start(ctx, "op")`,
+			// and can be ended anywhere.
+			if config.SameFuncEnd && !disabled[EndCheck] {
+				config.report(pass, EndCheck, lhs, "%s must be ended in the function that starts it", types.ExprString(lhs))
+			}
+			return true
+		} else if id == nil {
+			if !disabled[EndCheck] {
+				config.report(pass, EndCheck, n, "span is unassigned, probable memory leak")
+			}
+			return true
+		}
+
+		if id.Name == "_" && disabled[EndCheck] {
+			return true
+		} else if id.Name == "_" {
+			config.report(pass, EndCheck, id, "span is unassigned, probable memory leak")
+		} else if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok {
+			// If the span variable is defined outside function scope,
+			// do not analyze it.
+			if funcScope.Contains(v.Pos()) {
+				spanVars[id] = spanVar{
+					vr:       v,
+					stmt:     stmt,
+					id:       id,
+					spanType: sType,
+					name:     name,
+					noEnd:    hasNoEndDirective(pass, stmt),
+				}
+			}
+		} else if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok {
+			spanVars[id] = spanVar{
+				vr:       v,
+				stmt:     stmt,
+				id:       id,
+				spanType: sType,
+				name:     name,
+				noEnd:    hasNoEndDirective(pass, stmt),
+			}
+		}
+
+		return true
+	})
+
+	if decl, ok := node.(*ast.FuncDecl); ok && config.mustHaveSpanEnabled && !disabled[MustHaveSpanCheck] {
+		// Check if a function taking a context starts a span.
+		reportMissingSpan(pass, config, decl)
+	}
+
+	if config.nilTracerEnabled && !disabled[NilTracerCheck] {
+		// Check if spans are started with a tracer that may be nil.
+		reportNilTracers(pass, config, node)
+	}
+
+	if config.loopSpanEnabled && !disabled[LoopSpanCheck] {
+		// Check if spans are started unconditionally in loops.
+		reportLoopSpans(pass, config, node)
+	}
+
+	if config.lateSpanEnabled && !disabled[LateSpanCheck] {
+		// Check if errors are checked before the function's first span is started.
+		reportLateSpan(pass, config, node, spanVars)
+	}
+
+	// Spans created another way and attached to a context are checked like started spans.
+	for id, sv := range getAttachedSpans(pass.TypesInfo, node, funcScope, spanVars) {
+		spanVars[id] = sv
+	}
+
+	if len(spanVars) == 0 {
+		return nil // no need to inspect CFG
+	}
+
+	// Calls on variables the span is assigned to count as calls on the span.
+	for id, sv := range spanVars {
+		sv.aliases = getAliases(pass.TypesInfo, node, sv.vr)
+		sv.refs = getSpanRefs(pass.TypesInfo, node, sv)
+		sv.endCalls = getEndFuncCalls(pass.TypesInfo, node, sv, config.endFuncs)
+		sv.deferredEndCalls = getDeferredEndFuncCalls(pass.TypesInfo, node, sv, config.deferredEndFuncs)
+		if !config.SameFuncEnd {
+			sv.returns = getSpanReturns(pass.TypesInfo, node, sv)
+		}
+		var calls map[*ast.CallExpr]bool
+		sv.endValues, calls = getEndMethodValues(pass.TypesInfo, node, sv)
+		for call := range getCleanupAppends(pass.TypesInfo, node, sv) {
+			calls[call] = true
+		}
+		for call := range calls {
+			if sv.endCalls == nil {
+				sv.endCalls = make(map[*ast.CallExpr]bool)
+			}
+			sv.endCalls[call] = true
+		}
+		spanVars[id] = sv
+	}
+
+	// Obtain the CFG.
+	cfgs := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
+	var g *cfg.CFG
+	var sig *types.Signature
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		sig, _ = pass.TypesInfo.Defs[node.Name].Type().(*types.Signature)
+		g = cfgs.FuncDecl(node)
+	case *ast.FuncLit:
+		sig, _ = pass.TypesInfo.Types[node.Type].Type.(*types.Signature)
+		g = cfgs.FuncLit(node)
+	}
+	if sig == nil {
+		return nil // missing type information
+	}
+
+	if n := countNodes(g); config.MaxFuncNodes > 0 && n > config.MaxFuncNodes {
+		for _, sv := range spanVars {
+			config.explainf(pass, EndCheck, sv, "skipped, function has %d nodes, more than max-func-nodes %d", n, config.MaxFuncNodes)
+		}
+		return nil // too large to analyze
+	}
+
+	// Tests often do not set the status of, or record errors on, their spans.
+	relaxed := config.RelaxTestFiles && isTestFile(pass, node)
+
+	// Check for missing calls.
+	for _, sv := range spanVars {
+		if config.Explain {
+			checks := make([]Check, 0, len(disabled))
+			for check := range disabled {
+				checks = append(checks, check)
+			}
+			slices.Sort(checks)
+			for _, check := range checks {
+				config.explainf(pass, check, sv, "disabled by %s directive", disableDirective)
+			}
+		}
+
+		if config.endCheckEnabled && !disabled[EndCheck] && config.SkipMainEnd && isMain(pass, node) {
+			config.explainf(pass, EndCheck, sv, "skipped, spans started in main are ended at shutdown")
+		} else if config.endCheckEnabled && !disabled[EndCheck] && sv.noEnd {
+			config.explainf(pass, EndCheck, sv, "disabled by %s directive", noEndDirective)
+		} else if config.endCheckEnabled && !disabled[EndCheck] {
+			// Check if there's no End to the span.
+			if rets, leaks := getMissingSpanCalls(pass, g, sv, []string{"End"}, func(_ *analysis.Pass, ret *ast.ReturnStmt) *ast.ReturnStmt { return ret }, nil, config.noReturnFuncs, config.startSpanMatchers, config.AllPaths, false, config.explainPath(pass, EndCheck, sv, "End")); len(rets) > 0 {
+				confidence := getConfidence(pass, config, node, sv, []string{"End"}, leaks)
+				var fixes []analysis.SuggestedFix
+				if confidence == ConfidenceHigh && !isSpanCalled(pass, node, sv, []string{"End"}) {
+					// The span is never ended, so End can be deferred.
+					fixes = getDeferEndFix(pass, node, sv)
+				}
+				config.reportFixes(pass, EndCheck, confidence, sv.stmt, fixes, "%s.End is not called on all paths, possible memory leak%s", sv.vr.Name(), sv.label())
+				for _, ret := range rets {
+					reportReachable(pass, config, EndCheck, confidence, ret, sv, "End", "never returns")
+				}
+			} else {
+				config.explainf(pass, EndCheck, sv, "%s.End is called on all paths", sv.vr.Name())
+			}
+		}
+
+		if config.endCheckEnabled && !disabled[EndCheck] && config.DeferWithin > 0 {
+			// Check if the span's End is deferred soon after it is started.
+			reportDistantDefer(pass, config, node, sv)
+		}
+
+		if relaxed {
+			config.explainf(pass, SetStatusCheck, sv, "skipped in test file")
+			config.explainf(pass, RecordErrorCheck, sv, "skipped in test file")
+		}
+
+		if config.setStatusEnabled && !disabled[SetStatusCheck] && !relaxed {
+			selNames := []string{"SetStatus"}
+			if config.RecordErrorSatisfiesSetStatus {
+				selNames = append(selNames, "RecordError")
+			}
+
+			// Check if there's no SetStatus to the span setting an error.
+			if rets, leaks := getMissingSpanCalls(pass, g, sv, selNames, getErrorReturn, config.ignoreChecksSignatures, config.panicOnErrorFuncs, config.startSpanMatchers, config.AllPaths, false, config.explainPath(pass, SetStatusCheck, sv, "SetStatus")); len(rets) > 0 {
+				confidence := getConfidence(pass, config, node, sv, selNames, leaks)
+				config.reportConfidence(pass, SetStatusCheck, confidence, sv.stmt, "%s.SetStatus is not called on all paths%s", sv.vr.Name(), sv.label())
+				for _, ret := range rets {
+					reportReachable(pass, config, SetStatusCheck, confidence, ret, sv, "SetStatus", "panics on error")
+				}
+			} else {
+				config.explainf(pass, SetStatusCheck, sv, "%s.SetStatus is called on all paths returning an error", sv.vr.Name())
+			}
+
+			if config.StrictRecoveredErrors {
+				// Check if a panic recovered as an error is returned without SetStatus.
+				reportRecoveredErrors(pass, config, SetStatusCheck, node, sv, selNames, "SetStatus")
+			}
+		}
+
+		if config.endBeforeGoroutineEnabled && !disabled[EndBeforeGoroutineCheck] {
+			// Check if the span is ended before a goroutine using its context.
+			reportEndBeforeGoroutine(pass, config, node, g, sv)
+		}
+
+		if config.requestContextEnabled && !disabled[RequestContextCheck] {
+			// Check if the span's context is propagated to the request passed on.
+			reportUnpropagatedRequestContext(pass, config, node, sv)
+		}
+
+		if config.returnedContextEnabled && !disabled[ReturnedContextCheck] {
+			// Check if the span's context is returned while the span is ended in a defer.
+			reportReturnedEndedContext(pass, config, node, sv)
+		}
+
+		if config.deadSpanEnabled && !disabled[DeadSpanCheck] {
+			// Check if the span is ended without being used.
+			reportDeadSpan(pass, config, node, sv)
+		}
+
+		if config.unusedContextEnabled && !disabled[UnusedContextCheck] {
+			// Check if the context the span was started from is used instead of its own.
+			reportUnusedContext(pass, config, node, sv)
+		}
+
+		if config.recordErrorEnabled && !disabled[RecordErrorCheck] && !relaxed && sv.spanType == spanOpenTelemetry { // RecordError only exists in OpenTelemetry
+			// Check if there's no RecordError to the span setting an error.
+			if rets, leaks := getMissingSpanCalls(pass, g, sv, []string{"RecordError"}, getErrorReturn, config.ignoreChecksSignatures, config.panicOnErrorFuncs, config.startSpanMatchers, config.AllPaths || config.StrictRecordError, config.StrictRecordError, config.explainPath(pass, RecordErrorCheck, sv, "RecordError")); len(rets) > 0 {
+				confidence := getConfidence(pass, config, node, sv, []string{"RecordError"}, leaks)
+				config.reportConfidence(pass, RecordErrorCheck, confidence, sv.stmt, "%s.RecordError is not called on all paths%s", sv.vr.Name(), sv.label())
+				for _, ret := range rets {
+					reportReachable(pass, config, RecordErrorCheck, confidence, ret, sv, "RecordError", "panics on error")
+				}
+			} else {
+				config.explainf(pass, RecordErrorCheck, sv, "%s.RecordError is called on all paths returning an error", sv.vr.Name())
+			}
+
+			if config.StrictRecoveredErrors {
+				// Check if a panic recovered as an error is returned without RecordError.
+				reportRecoveredErrors(pass, config, RecordErrorCheck, node, sv, []string{"RecordError"}, "RecordError")
+			}
+		} else if config.recordErrorEnabled && !disabled[RecordErrorCheck] && !relaxed {
+			config.explainf(pass, RecordErrorCheck, sv, "skipped, only OpenTelemetry spans have RecordError")
+		}
+	}
+
+	if len(config.CustomChecks) > 0 {
+		runCustomChecks(pass, config, g, spanVars)
+	}
+
+	if config.endCheckEnabled && !disabled[EndCheck] {
+		// Check if one span is ended in place of another.
+		reportWrongSpanEnded(pass, config, node, spanVars)
+	}
+
+	if config.useAfterEndEnabled && !disabled[UseAfterEndCheck] {
+		// Check if a span is used after it is ended.
+		reportUseAfterEnd(pass, config, g, spanVars)
+	}
+
+	if config.setStatusEnabled && !disabled[SetStatusCheck] && !relaxed && config.StrictSetStatusCode {
+		// Check if a span's status is set with a code that cannot be verified.
+		reportNonConstantStatusCodes(pass, config, node, spanVars)
+	}
+
+	if config.recordErrorMismatchEnabled && !disabled[RecordErrorMismatchCheck] && !relaxed {
+		// Check if a span records a different error than the one returned.
+		reportRecordedErrorMismatch(pass, config, node, spanVars)
+	}
+
+	if config.endErrorEnabled && !disabled[EndErrorCheck] {
+		// Check if errors returned by a span's End are dropped.
+		reportIgnoredEndErrors(pass, config, node, spanVars)
+	}
+
+	if config.isRecordingEnabled && !disabled[IsRecordingCheck] {
+		// Check if expensive span attributes are computed for spans that are not recording.
+		reportUnguardedAttributes(pass, config, node, spanVars)
+	}
+
+	if config.doubleEndEnabled && !disabled[DoubleEndCheck] {
+		// Check if a span is ended directly after its End is deferred.
+		reportDoubleEnd(pass, config, g, spanVars)
+	}
+
+	if config.lockedEndEnabled && !disabled[LockedEndCheck] {
+		// Check if a span is ended while a mutex is locked.
+		reportLockedEnd(pass, config, g, spanVars)
+	}
+
+	if config.spanTypeEnabled && !disabled[SpanTypeCheck] {
+		// Check if a span is ended through a variable of another type.
+		reportSpanTypeMismatch(pass, config, node, spanVars)
+	}
+
+	if config.deferOrderEnabled && !disabled[DeferOrderCheck] {
+		// Check if a deferred span operation runs after the deferred End.
+		reportDeferOrder(pass, config, g, spanVars)
+	}
+
+	if config.endCheckEnabled && !disabled[EndCheck] && config.SameFuncEnd {
+		// Check if a span escapes the function that starts it.
+		reportEscapingSpans(pass, config, node, spanVars)
+	}
+
+	if config.endStyleEnabled && !disabled[EndStyleCheck] {
+		return getEndCalls(pass.TypesInfo, node, spanVars)
+	}
+	return nil
+}
+
+// reportEscapingSpans reports spans that are returned or passed elsewhere, and so may
+// be ended outside of the function that starts them.
+func reportEscapingSpans(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	reported := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		if reported[sv.vr] || sv.noEnd {
+			continue
+		}
+		reported[sv.vr] = true
+
+		for _, use := range getEscapes(pass, node, sv.vr, config.ignoreChecksSignatures) {
+			if isEndFuncArg(sv, use) {
+				continue // passing the span to an end function ends it
+			}
+			config.report(pass, EndCheck, use, "%s must be ended in the function that starts it", sv.vr.Name())
+		}
+	}
+}
+
+// getEscapes returns the uses of v in node that let it escape the function: it being
+// returned, passed to a function, sent on a channel, or stored in a field, element or
+// composite literal. Passing v to a function matching ignoreCheckSig is not an escape.
+func getEscapes(pass *analysis.Pass, node ast.Node, v *types.Var, ignoreCheckSig *regexp.Regexp) []ast.Node {
+	isSpan := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+
+	var escapes []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range n.Results {
+				if isSpan(r) {
+					escapes = append(escapes, r)
+				}
+			}
+		case *ast.CallExpr:
+			if obj := calleeObject(pass.TypesInfo, n); obj != nil && ignoreCheckSig != nil && ignoreCheckSig.MatchString(obj.String()) {
+				break
+			}
+			for _, arg := range n.Args {
+				if isSpan(arg) {
+					escapes = append(escapes, arg)
+				}
+			}
+		case *ast.SendStmt:
+			if isSpan(n.Value) {
+				escapes = append(escapes, n.Value)
+			}
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				if i >= len(n.Lhs) || !isSpan(rhs) {
+					continue
+				}
+				if _, ok := n.Lhs[i].(*ast.Ident); !ok {
+					escapes = append(escapes, rhs)
+				}
+			}
+		case *ast.CompositeLit:
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if isSpan(elt) {
+					escapes = append(escapes, elt)
+				}
+			}
+		}
+		return true
+	})
+
+	return escapes
+}
+
+// reportWrongSpanEnded reports spans that are never ended in a function where another
+// span of the same type is ended more than once. This is usually a typo, eg calling
+// span2.End() where span1.End() was intended.
+func reportWrongSpanEnded(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	// Count the End calls on each span variable.
+	endCalls := make(map[*types.Var]int)
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "End" {
+			return true
+		}
+
+		if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok {
+				endCalls[v]++
+			}
+		}
+
+		return true
+	})
+
+	// A variable can be assigned more than one span, keep its first definition.
+	spansByVar := make(map[*types.Var]spanVar)
+	for _, sv := range spanVars {
+		if prev, ok := spansByVar[sv.vr]; !ok || sv.stmt.Pos() < prev.stmt.Pos() {
+			spansByVar[sv.vr] = sv
+		}
+	}
+
+	spans := make([]spanVar, 0, len(spansByVar))
+	for _, sv := range spansByVar {
+		spans = append(spans, sv)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].stmt.Pos() < spans[j].stmt.Pos() })
+
+	for _, missing := range spans {
+		if missing.noEnd {
+			continue // the span is not expected to be ended
+		}
+
+		ends := endCalls[missing.vr] + len(missing.endCalls)
+		for alias := range missing.aliases {
+			ends += endCalls[alias]
+		}
+		if ends > 0 {
+			continue
+		}
+
+		for _, ended := range spans {
+			if ended.spanType == missing.spanType && endCalls[ended.vr] > 1 {
+				config.report(
+					pass,
+					EndCheck,
+					missing.stmt,
+					"%s.End is never called but %s.End is called %d times, possible wrong span ended",
+					missing.vr.Name(),
+					ended.vr.Name(),
+					endCalls[ended.vr],
+				)
+				break
+			}
+		}
+	}
+}
+
+// getAliases returns the variables in node that are assigned the span v, directly
+// or through another alias, eg s in `s := span`. Assignments are followed regardless
+// of control flow.
+func getAliases(info *types.Info, node ast.Node, v *types.Var) map[*types.Var]bool {
+	aliases := make(map[*types.Var]bool)
+	isSpan := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		u, ok := info.Uses[id].(*types.Var)
+		return ok && (u == v || aliases[u])
+	}
+
+	for changed := true; changed; {
+		changed = false
+		add := func(lhs *ast.Ident, rhs ast.Expr) {
+			if a, ok := info.ObjectOf(lhs).(*types.Var); ok && a != v && !aliases[a] && isSpan(rhs) {
+				aliases[a] = true
+				changed = true
+			}
+		}
+
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) != len(n.Rhs) {
+					break
+				}
+				for i, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						add(id, n.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) != len(n.Values) {
+					break
+				}
+				for i, id := range n.Names {
+					add(id, n.Values[i])
+				}
+			}
+			return true
+		})
+	}
+
+	return aliases
+}
+
+// getSpanRefs returns the variables in node that are assigned a pointer to the span or one of
+// its aliases, eg `p := &span`, a composite literal holding it, eg `spans := []trace.Span{span}`,
+// or that it is stored in, eg spans in `spans[key] = span`.
+func getSpanRefs(info *types.Info, node ast.Node, sv spanVar) map[*types.Var]bool {
+	isSpan := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		u, ok := info.Uses[id].(*types.Var)
+		return ok && (u == sv.vr || sv.aliases[u])
+	}
+	var holdsSpan func(e ast.Expr) bool
+	holdsSpan = func(e ast.Expr) bool {
+		switch e := ast.Unparen(e).(type) {
+		case *ast.UnaryExpr:
+			return e.Op == token.AND && isSpan(e.X)
+		case *ast.CompositeLit:
+			for _, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if isSpan(elt) || holdsSpan(elt) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	refs := make(map[*types.Var]bool)
+	add := func(lhs ast.Expr) {
+		if id, ok := getRefRoot(lhs).(*ast.Ident); ok {
+			if v, ok := info.ObjectOf(id).(*types.Var); ok {
+				refs[v] = true
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, lhs := range n.Lhs {
+				if _, ok := ast.Unparen(lhs).(*ast.IndexExpr); ok && isSpan(n.Rhs[i]) || holdsSpan(n.Rhs[i]) {
+					add(lhs)
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) != len(n.Values) {
+				break
+			}
+			for i, id := range n.Names {
+				if holdsSpan(n.Values[i]) {
+					add(id)
+				}
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+// getRefRoot returns the expression that x dereferences or indexes, eg p in `*p` and spans
+// in `spans[0]`.
+func getRefRoot(x ast.Expr) ast.Expr {
+	for {
+		switch e := ast.Unparen(x).(type) {
+		case *ast.StarExpr:
+			x = e.X
+		case *ast.IndexExpr:
+			x = e.X
+		default:
+			return ast.Unparen(x)
+		}
+	}
+}
+
+// getEndFuncCalls returns the calls in node to functions matching endFuncSig that are
+// passed the span or one of its aliases, eg FinishSpan(ctx, span).
+func getEndFuncCalls(info *types.Info, node ast.Node, sv spanVar, endFuncSig *regexp.Regexp) map[*ast.CallExpr]bool {
+	if endFuncSig == nil {
+		return nil
+	}
+
+	calls := make(map[*ast.CallExpr]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		if obj := calleeObject(info, call); obj == nil || !endFuncSig.MatchString(obj.String()) {
+			return true
+		}
+
+		for _, arg := range call.Args {
+			if id, ok := ast.Unparen(arg).(*ast.Ident); ok && (info.Uses[id] == sv.vr || sv.isAlias(info, id)) {
+				calls[call] = true
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// getEndMethodValues returns the span.End method values of the span or one of its aliases
+// that are bound to variables, eg in `end := span.End`, and the calls to those variables.
+func getEndMethodValues(info *types.Info, node ast.Node, sv spanVar) (map[*ast.SelectorExpr]bool, map[*ast.CallExpr]bool) {
+	values := make(map[*ast.SelectorExpr]bool)
+	vars := make(map[types.Object]bool)
+	bind := func(lhs *ast.Ident, rhs ast.Expr) {
+		sel, ok := ast.Unparen(rhs).(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "End" {
+			return
+		}
+		id, ok := ast.Unparen(sel.X).(*ast.Ident)
+		if !ok || info.Uses[id] != sv.vr && !sv.isAlias(info, id) {
+			return
+		}
+
+		values[sel] = true
+		if obj := info.ObjectOf(lhs); obj != nil {
+			vars[obj] = true
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					bind(id, n.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) != len(n.Values) {
+				break
+			}
+			for i, id := range n.Names {
+				bind(id, n.Values[i])
+			}
+		}
+		return true
+	})
+
+	calls := make(map[*ast.CallExpr]bool)
+	if len(vars) == 0 {
+		return values, calls
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok && vars[info.Uses[id]] {
+				calls[call] = true
+			}
+		}
+		return true
+	})
+	return values, calls
+}
+
+// getSpanReturns returns the return statements of node, but not of nested functions, that
+// return the span or one of its aliases, eg `return ctx, span`.
+func getSpanReturns(info *types.Info, node ast.Node, sv spanVar) map[*ast.ReturnStmt]bool {
+	rets := make(map[*ast.ReturnStmt]bool)
+	inspectFunc(node, func(n ast.Node) {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return
+		}
+
+		for _, r := range ret.Results {
+			if id, ok := ast.Unparen(r).(*ast.Ident); ok && (info.Uses[id] == sv.vr || sv.isAlias(info, id)) {
+				rets[ret] = true
+			}
+		}
+	})
+	return rets
+}
+
+// getCleanupAppends returns the calls in node that append the span or one of its aliases to
+// a slice that is ranged over to end its spans, eg spans = append(spans, span) with
+// `for _, s := range spans { s.End() }`.
+func getCleanupAppends(info *types.Info, node ast.Node, sv spanVar) map[*ast.CallExpr]bool {
+	cleaned := make(map[types.Object]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if rng, ok := n.(*ast.RangeStmt); ok && rangeEndsSpans(info, rng) {
+			cleaned[info.ObjectOf(rng.X.(*ast.Ident))] = true
+		}
+		return true
+	})
+	if len(cleaned) == 0 {
+		return nil
+	}
+
+	calls := make(map[*ast.CallExpr]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		if fn, ok := ast.Unparen(call.Fun).(*ast.Ident); !ok || fn.Name != "append" || info.Uses[fn] != types.Universe.Lookup("append") {
+			return true
+		}
+		if slice, ok := ast.Unparen(call.Args[0]).(*ast.Ident); !ok || !cleaned[info.ObjectOf(slice)] {
+			return true
+		}
+
+		for _, arg := range call.Args[1:] {
+			if id, ok := ast.Unparen(arg).(*ast.Ident); ok && (info.Uses[id] == sv.vr || sv.isAlias(info, id)) {
+				calls[call] = true
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// rangeEndsSpans reports whether rng ranges over a slice variable and ends each element,
+// either with the value, eg `for _, s := range spans { s.End() }`, or by index, eg
+// `for i := range spans { spans[i].End() }`.
+func rangeEndsSpans(info *types.Info, rng *ast.RangeStmt) bool {
+	slice, ok := rng.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	key, _ := rng.Key.(*ast.Ident)
+	value, _ := rng.Value.(*ast.Ident)
+
+	found := false
+	ast.Inspect(rng.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "End" || found {
+			return !found
+		}
+
+		switch x := ast.Unparen(sel.X).(type) {
+		case *ast.Ident:
+			found = value != nil && value.Name != "_" && info.Uses[x] == info.ObjectOf(value)
+		case *ast.IndexExpr:
+			s, ok := x.X.(*ast.Ident)
+			i, ok2 := x.Index.(*ast.Ident)
+			found = ok && ok2 && key != nil && key.Name != "_" &&
+				info.Uses[s] == info.ObjectOf(slice) && info.Uses[i] == info.ObjectOf(key)
+		}
+		return !found
+	})
+	return found
 }
 
-// runFunc checks if the node is a function, has a span, and the span never has SetStatus set.
-func runFunc(pass *analysis.Pass, node ast.Node, config *Config) {
-	// copying https://cs.opensource.google/go/x/tools/+/master:go/analysis/passes/lostcancel/lostcancel.go
-
-	// Find scope of function node
-	var funcScope *types.Scope
-	switch v := node.(type) {
-	case *ast.FuncLit:
-		funcScope = pass.TypesInfo.Scopes[v.Type]
-	case *ast.FuncDecl:
-		funcScope = pass.TypesInfo.Scopes[v.Type]
-		fnSig := pass.TypesInfo.ObjectOf(v.Name).String()
+// getDeferredEndFuncCalls returns the calls in node's defers to functions matching
+// deferredEndFuncSig that are passed the span or one of its aliases, mapped to whether they
+// are also passed a pointer to an error. The calls are either deferred, eg
+// `defer finishSpan(span, &err)`, or in a deferred function literal, eg
+// `defer func() { finishSpan(span, &err) }()`.
+func getDeferredEndFuncCalls(info *types.Info, node ast.Node, sv spanVar, deferredEndFuncSig *regexp.Regexp) map[*ast.CallExpr]bool {
+	if deferredEndFuncSig == nil {
+		return nil
+	}
 
-		// Skip checking spans in this function if it's a custom starter/creator.
-		if config.startSpanMatchersCustomRegex != nil && config.startSpanMatchersCustomRegex.MatchString(fnSig) {
+	calls := make(map[*ast.CallExpr]bool)
+	addCall := func(call *ast.CallExpr) {
+		if obj := calleeObject(info, call); obj == nil || !deferredEndFuncSig.MatchString(obj.String()) {
 			return
 		}
-	}
 
-	// Maps each span variable to its defining ValueSpec/AssignStmt.
-	spanVars := make(map[*ast.Ident]spanVar)
-
-	// Find the set of span vars to analyze.
-	stack := make([]ast.Node, 0, stackLen)
-	ast.Inspect(node, func(n ast.Node) bool {
-		switch n.(type) {
-		case *ast.FuncLit:
-			if len(stack) > 0 {
-				return false // don't stray into nested functions
+		passed, errPtr := false, false
+		for _, arg := range call.Args {
+			switch arg := ast.Unparen(arg).(type) {
+			case *ast.Ident:
+				passed = passed || info.Uses[arg] == sv.vr || sv.isAlias(info, arg)
+			case *ast.UnaryExpr:
+				errPtr = errPtr || arg.Op == token.AND && isErrorType(info.TypeOf(arg.X))
 			}
-		case nil:
-			stack = stack[:len(stack)-1] // pop
-			return true
 		}
-		stack = append(stack, n) // push
-
-		// Look for [{AssignStmt,ValueSpec} CallExpr SelectorExpr]:
-		//
-		//   ctx, span     := otel.Tracer("app").Start(...)
-		//   ctx, span     = otel.Tracer("app").Start(...)
-		//   var ctx, span = otel.Tracer("app").Start(...)
-		sType, isStart := isSpanStart(pass.TypesInfo, n, config.startSpanMatchers)
-		if !isStart {
-			return true
+		if passed {
+			calls[call] = errPtr
 		}
+	}
 
-		if !isCall(stack[len(stack)-2]) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.DeferStmt)
+		if !ok {
 			return true
 		}
 
-		stmt := stack[len(stack)-3]
-		id := getID(stmt)
-		if id == nil {
-			pass.ReportRangef(n, "span is unassigned, probable memory leak")
-			return true
+		addCall(stmt.Call)
+		if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+			ast.Inspect(lit.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					addCall(call)
+				}
+				return true
+			})
 		}
+		return false
+	})
 
-		if id.Name == "_" {
-			pass.ReportRangef(id, "span is unassigned, probable memory leak")
-		} else if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok {
-			// If the span variable is defined outside function scope,
-			// do not analyze it.
-			if funcScope.Contains(v.Pos()) {
-				spanVars[id] = spanVar{
-					vr:       v,
-					stmt:     stmt,
-					id:       id,
-					spanType: sType,
-				}
+	return calls
+}
+
+// isEndFuncArg reports whether n is an argument of one of the span's end function or deferred
+// end function calls.
+func isEndFuncArg(sv spanVar, n ast.Node) bool {
+	for call := range sv.endCalls {
+		for _, arg := range call.Args {
+			if arg == n {
+				return true
 			}
-		} else if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok {
-			spanVars[id] = spanVar{
-				vr:       v,
-				stmt:     stmt,
-				id:       id,
-				spanType: sType,
+		}
+	}
+	for call := range sv.deferredEndCalls {
+		for _, arg := range call.Args {
+			if arg == n {
+				return true
 			}
 		}
+	}
+	return false
+}
 
-		return true
-	})
+// isTestFile reports whether node is in a _test.go file.
+func isTestFile(pass *analysis.Pass, node ast.Node) bool {
+	return strings.HasSuffix(pass.Fset.Position(node.Pos()).Filename, "_test.go")
+}
 
-	if len(spanVars) == 0 {
-		return // no need to inspect CFG
+// isMain reports whether node is the main function of package main.
+func isMain(pass *analysis.Pass, node ast.Node) bool {
+	decl, ok := node.(*ast.FuncDecl)
+	return ok && decl.Recv == nil && decl.Name.Name == "main" && pass.Pkg.Name() == "main"
+}
+
+// label returns the span's name to append to its diagnostics, eg ` (span "fetch-user")`, to
+// tell apart spans in functions with several. It is empty if the name is not a constant, and
+// the span's variable name identifies it.
+func (sv spanVar) label() string {
+	if sv.name == "" {
+		return ""
 	}
+	return fmt.Sprintf(" (span %q)", sv.name)
+}
 
-	// Obtain the CFG.
-	cfgs := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
-	var g *cfg.CFG
-	var sig *types.Signature
-	switch node := node.(type) {
-	case *ast.FuncDecl:
-		sig, _ = pass.TypesInfo.Defs[node.Name].Type().(*types.Signature)
-		g = cfgs.FuncDecl(node)
-	case *ast.FuncLit:
-		sig, _ = pass.TypesInfo.Types[node.Type].Type.(*types.Signature)
-		g = cfgs.FuncLit(node)
+// getSpanName returns the first constant string argument of the call starting a span, which
+// is its name for the built-in start signatures, eg "bar" in `tracer.Start(ctx, "bar")`.
+func getSpanName(info *types.Info, n ast.Node) string {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return ""
 	}
-	if sig == nil {
-		return // missing type information
+	for _, arg := range call.Args {
+		if tv, ok := info.Types[arg]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value)
+		}
 	}
+	return ""
+}
 
-	// Check for missing calls.
-	for _, sv := range spanVars {
-		if config.endCheckEnabled {
-			// Check if there's no End to the span.
-			if ret := getMissingSpanCalls(pass, g, sv, "End", func(_ *analysis.Pass, ret *ast.ReturnStmt) *ast.ReturnStmt { return ret }, nil, config.startSpanMatchers); ret != nil {
-				pass.ReportRangef(sv.stmt, "%s.End is not called on all paths, possible memory leak", sv.vr.Name())
-				pass.ReportRangef(ret, "return can be reached without calling %s.End", sv.vr.Name())
-			}
+// isRef reports whether x dereferences or indexes the span, one of its aliases or one of its
+// refs, eg `(*p)` after `p := &span`.
+func (sv spanVar) isRef(info *types.Info, x ast.Expr) bool {
+	if root := getRefRoot(x); root != ast.Unparen(x) {
+		id, ok := root.(*ast.Ident)
+		v, isVar := info.Uses[id].(*types.Var)
+		return ok && isVar && (v == sv.vr || sv.aliases[v] || sv.refs[v])
+	}
+	return false
+}
+
+// isChained reports whether x is a chain of method calls on the span, or one of its aliases,
+// that each return the span, eg `span.SetAttr("k", "v")` in `span.SetAttr("k", "v").End()` of
+// a fluent span API.
+func (sv spanVar) isChained(info *types.Info, x ast.Expr) bool {
+	for {
+		call, ok := ast.Unparen(x).(*ast.CallExpr)
+		if !ok || !types.Identical(info.TypeOf(call), sv.vr.Type()) {
+			return false
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return false
 		}
 
-		if config.setStatusEnabled {
-			// Check if there's no SetStatus to the span setting an error.
-			if ret := getMissingSpanCalls(pass, g, sv, "SetStatus", getErrorReturn, config.ignoreChecksSignatures, config.startSpanMatchers); ret != nil {
-				pass.ReportRangef(sv.stmt, "%s.SetStatus is not called on all paths", sv.vr.Name())
-				pass.ReportRangef(ret, "return can be reached without calling %s.SetStatus", sv.vr.Name())
-			}
+		x = sel.X
+		if id, ok := ast.Unparen(x).(*ast.Ident); ok {
+			v, _ := info.Uses[id].(*types.Var)
+			return v != nil && (v == sv.vr || sv.aliases[v])
 		}
+	}
+}
 
-		if config.recordErrorEnabled && sv.spanType == spanOpenTelemetry { // RecordError only exists in OpenTelemetry
-			// Check if there's no RecordError to the span setting an error.
-			if ret := getMissingSpanCalls(pass, g, sv, "RecordError", getErrorReturn, config.ignoreChecksSignatures, config.startSpanMatchers); ret != nil {
-				pass.ReportRangef(sv.stmt, "%s.RecordError is not called on all paths", sv.vr.Name())
-				pass.ReportRangef(ret, "return can be reached without calling %s.RecordError", sv.vr.Name())
-			}
+// isAlias reports whether id refers to one of the span's aliases.
+func (sv spanVar) isAlias(info *types.Info, id *ast.Ident) bool {
+	v, ok := info.Uses[id].(*types.Var)
+	return ok && sv.aliases[v]
+}
+
+// reportReachable reports that n, a return statement or a terminal call described by
+// callDesc, can be reached without calling selName on the span.
+func reportReachable(pass *analysis.Pass, config *Config, check Check, confidence string, n ast.Node, sv spanVar, selName, callDesc string) {
+	if call, ok := n.(*ast.CallExpr); ok {
+		config.reportConfidence(pass, check, confidence, call, "%s %s and can be reached without calling %s.%s%s", calleeName(pass.TypesInfo, call), callDesc, sv.vr.Name(), selName, sv.label())
+		return
+	}
+
+	config.reportConfidence(pass, check, confidence, n, "return can be reached without calling %s.%s%s", sv.vr.Name(), selName, sv.label())
+}
+
+// getConfidence returns how confident a finding is that none of selNames is called on the span
+// on the given number of leaking paths. It is low if the span escapes the function, since it
+// may be handled elsewhere, and medium if a deferred call uses the span, since it may make the
+// call in a way that isn't followed, or if one of selNames is called on other paths but missed
+// on more than one, as in complex control flow. It is high otherwise, eg when the call is never
+// made or is missed on a single path.
+func getConfidence(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar, selNames []string, leaks int) string {
+	if len(getEscapes(pass, node, sv.vr, config.ignoreChecksSignatures)) > 0 {
+		return ConfidenceLow
+	}
+
+	deferred := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if d, ok := n.(*ast.DeferStmt); ok {
+			ast.Inspect(d.Call, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && (pass.TypesInfo.Uses[id] == sv.vr || sv.isAlias(pass.TypesInfo, id)) {
+					deferred = true
+				}
+				return !deferred
+			})
 		}
+		return !deferred
+	})
+	if deferred || (leaks > 1 && isSpanCalled(pass, node, sv, selNames)) {
+		return ConfidenceMedium
 	}
+
+	return ConfidenceHigh
+}
+
+// isSpanCalled reports whether one of selNames is called on the span, or one of its aliases,
+// anywhere in node.
+func isSpanCalled(pass *analysis.Pass, node ast.Node, sv spanVar, selNames []string) bool {
+	called := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || !slices.Contains(selNames, sel.Sel.Name) {
+			return !called
+		}
+
+		if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok && (pass.TypesInfo.Uses[id] == sv.vr || sv.isAlias(pass.TypesInfo, id)) {
+			called = true
+		}
+		return !called
+	})
+	return called
 }
 
-// isSpanStart reports whether n is tracer.Start()
+// isSpanStart reports whether n is tracer.Start(), or a span start function
+// that is dot-imported, eg StartSpan() after `import . "go.opencensus.io/trace"`.
 func isSpanStart(info *types.Info, n ast.Node, startSpanMatchers []spanStartMatcher) (spanType, bool) {
-	sel, ok := n.(*ast.SelectorExpr)
-	if !ok {
+	var fn types.Object
+	switch n := n.(type) {
+	case *ast.SelectorExpr:
+		fn = info.ObjectOf(n.Sel)
+	case *ast.Ident:
+		if f, ok := info.Uses[n].(*types.Func); ok {
+			fn = f
+		}
+	}
+	if fn == nil {
 		return spanUnset, false
 	}
 
-	fnSig := info.ObjectOf(sel.Sel).String()
+	fnSig := fn.String()
 
 	// Check if the function is a span start function
 	for _, matcher := range startSpanMatchers {
@@ -226,12 +1243,54 @@ func isSpanStart(info *types.Info, n ast.Node, startSpanMatchers []spanStartMatc
 	return 0, false
 }
 
-func isCall(n ast.Node) bool {
-	_, ok := n.(*ast.CallExpr)
-	return ok
+// isCall reports whether n is a call of fun.
+func isCall(n, fun ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	return ok && call.Fun == fun
+}
+
+// getCallIndex returns the index in stack of the call of its last node, skipping parentheses
+// around the function, eg in `(tracer.Start)(ctx, "op")`, or -1 if it is not called.
+func getCallIndex(stack []ast.Node) int {
+	i := len(stack) - 2
+	for ; i > 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+	}
+	if i < 0 || !isCall(stack[i], stack[i+1]) {
+		return -1
+	}
+	return i
+}
+
+// getStartStmt returns the statement a span is started in, given the stack of nodes enclosing
+// the call starting it. Parentheses, conversions and type assertions around the call, eg in
+// `span := trace.Span(util.StartSpan(ctx))`, are skipped.
+func getStartStmt(info *types.Info, stack []ast.Node) ast.Node {
+	i := len(stack) - 1
+	for ; i > 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ParenExpr, *ast.TypeAssertExpr:
+			continue
+		case *ast.CallExpr:
+			if tv, ok := info.Types[n.Fun]; ok && tv.IsType() && len(n.Args) == 1 {
+				continue // conversion
+			}
+		}
+		break
+	}
+	return stack[i]
 }
 
 func getID(node ast.Node) *ast.Ident {
+	id, _ := getSpanExpr(node).(*ast.Ident)
+	return id
+}
+
+// getSpanExpr returns the expression a span start assigns the span to, eg span in
+// `ctx, span := tracer.Start(ctx, "op")` or s.span in `s.ctx, s.span = tracer.Start(ctx, "op")`.
+func getSpanExpr(node ast.Node) ast.Expr {
 	switch stmt := node.(type) {
 	case *ast.ValueSpec:
 		if len(stmt.Names) > 1 {
@@ -241,33 +1300,53 @@ func getID(node ast.Node) *ast.Ident {
 		}
 	case *ast.AssignStmt:
 		if len(stmt.Lhs) > 1 {
-			id, _ := stmt.Lhs[1].(*ast.Ident)
-			return id
+			return stmt.Lhs[1]
 		} else if len(stmt.Lhs) == 1 {
-			id, _ := stmt.Lhs[0].(*ast.Ident)
-			return id
+			return stmt.Lhs[0]
 		}
 	}
 	return nil
 }
 
-// getMissingSpanCalls finds a path through the CFG, from stmt (which defines
-// the 'span' variable v) to a return statement, that doesn't call the passed selector on the span.
+// countNodes returns the number of nodes in all blocks of the CFG.
+func countNodes(g *cfg.CFG) int {
+	if g == nil {
+		return 0
+	}
+
+	n := 0
+	for _, b := range g.Blocks {
+		n += len(b.Nodes)
+	}
+	return n
+}
+
+// getMissingSpanCalls finds paths through the CFG, from stmt (which defines
+// the 'span' variable v) to return statements, that don't call any of the passed selectors on the span.
+// Calls to functions matching terminalCallSig, eg ones that never return or panic on
+// error, are treated like return statements.
+// Only the first return found is returned, and explained, unless allPaths is set, but the
+// search goes on to count all of the leaking paths, which is returned too.
 func getMissingSpanCalls(
 	pass *analysis.Pass,
 	g *cfg.CFG,
 	sv spanVar,
-	selName string,
+	selNames []string,
 	checkErr func(pass *analysis.Pass, ret *ast.ReturnStmt) *ast.ReturnStmt,
 	ignoreCheckSig *regexp.Regexp,
+	terminalCallSig *regexp.Regexp,
 	spanStartMatchers []spanStartMatcher,
-) *ast.ReturnStmt {
+	allPaths bool,
+	strict bool,
+	explain func(n ast.Node, path []*cfg.Block),
+) ([]ast.Node, int) {
 	// blockUses computes "uses" for each block, caching the result.
 	memo := make(map[*cfg.Block]bool)
 	blockUses := func(pass *analysis.Pass, b *cfg.Block) bool {
 		res, ok := memo[b]
 		if !ok {
-			res = usesCall(pass, b.Nodes, sv, selName, ignoreCheckSig, spanStartMatchers, 0)
+			nodes, _ := splitTerminalCall(pass.TypesInfo, b.Nodes, terminalCallSig)
+			res = usesCall(pass, nodes, sv, selNames, ignoreCheckSig, spanStartMatchers, 0)
 			memo[b] = res
 		}
 		return res
@@ -275,42 +1354,84 @@ func getMissingSpanCalls(
 
 	// Find the var's defining block in the CFG,
 	// plus the rest of the statements of that block.
-	var defBlock *cfg.Block
-	var rest []ast.Node
-outer:
-	for _, b := range g.Blocks {
-		for i, n := range b.Nodes {
-			if n == sv.stmt {
-				defBlock = b
-				rest = b.Nodes[i+1:]
-				break outer
-			}
+	defBlock, rest := getDefBlock(g, sv.stmt)
+
+	// Is the call "used" in the remainder of its defining block?
+	rest, noReturn := splitTerminalCall(pass.TypesInfo, rest, terminalCallSig)
+	if usesCall(pass, rest, sv, selNames, ignoreCheckSig, spanStartMatchers, 0) {
+		return nil, 0
+	}
+
+	// The path of blocks searched, for explaining how a node was reached.
+	path := []*cfg.Block{defBlock}
+	found := func(n ast.Node) {
+		if explain != nil {
+			explain(n, path)
 		}
 	}
 
-	// Is the call "used" in the remainder of its defining block?
-	if usesCall(pass, rest, sv, selName, ignoreCheckSig, spanStartMatchers, 0) {
-		return nil
+	// Does the defining block end in a call that never returns?
+	if noReturn != nil {
+		found(noReturn)
+		return []ast.Node{noReturn}, 1
 	}
 
 	// Does the defining block return without making the call?
 	if ret := defBlock.Return(); ret != nil {
-		return checkErr(pass, ret)
+		if ret := checkErr(pass, ret); ret != nil {
+			found(ret)
+			return []ast.Node{ret}, 1
+		}
+		return nil, 0
 	}
 
-	// Search the CFG depth-first for a path, from defblock to a
+	// Search the CFG depth-first for paths, from defblock to a
 	// return block, in which v is never "used".
+	var rets []ast.Node
+	seenRets := make(map[token.Pos]bool)
+	foundPath := func(n ast.Node) {
+		if seenRets[n.Pos()] {
+			return
+		}
+		seenRets[n.Pos()] = true
+		if allPaths || len(rets) == 0 {
+			rets = append(rets, n)
+			found(n)
+		}
+	}
+
+	// Spans that are not recording must still be ended, so only SetStatus and RecordError
+	// are not required on the branch of an IsRecording guard where the span is not recording.
+	ending := slices.Contains(selNames, "End")
+	succs := func(b *cfg.Block) []*cfg.Block {
+		if ending {
+			return b.Succs
+		}
+		return recordingSuccs(b, sv)
+	}
 	seen := make(map[*cfg.Block]bool)
-	var search func(blocks []*cfg.Block) *ast.ReturnStmt
-	search = func(blocks []*cfg.Block) *ast.ReturnStmt {
+	var search func(blocks []*cfg.Block)
+	var searchBlock func(b *cfg.Block)
+	search = func(blocks []*cfg.Block) {
 		for _, b := range blocks {
 			if seen[b] {
 				continue
 			}
 			seen[b] = true
 
-			// Skip successors that are not nested within this current block.
-			if _, ok := nestedBlockTypes[b.Kind]; !ok {
+			if strict {
+				// Follow every successor while the span is in scope and not reassigned.
+				if leavesScope(b, sv.vr) || assignsSpan(pass.TypesInfo, b.Nodes, sv.vr, spanStartMatchers) {
+					continue
+				}
+			} else if ending {
+				// Follow every successor until the span is reassigned, eg the code after an if
+				// statement that only ends the span in one of its branches.
+				if assignsSpan(pass.TypesInfo, b.Nodes, sv.vr, spanStartMatchers) {
+					continue
+				}
+			} else if _, ok := nestedBlockTypes[b.Kind]; !ok {
+				// Skip successors that are not nested within this current block.
 				continue
 			}
 
@@ -319,20 +1440,212 @@ outer:
 				continue
 			}
 
-			// Found path to return statement?
-			if ret := getErrorReturn(pass, b.Return()); ret != nil {
-				return ret // found
+			path = append(path, b)
+			searchBlock(b)
+			path = path[:len(path)-1]
+		}
+	}
+	searchBlock = func(b *cfg.Block) {
+		// Found path to a call that never returns?
+		if _, noReturn := splitTerminalCall(pass.TypesInfo, b.Nodes, terminalCallSig); noReturn != nil {
+			foundPath(noReturn)
+			return
+		}
+
+		// Found path to return statement?
+		if ret := checkErr(pass, b.Return()); ret != nil {
+			foundPath(ret)
+		}
+
+		// Recur
+		search(succs(b))
+	}
+	search(succs(defBlock))
+
+	return rets, len(seenRets)
+}
+
+// assignsSpan reports whether nodes assign a new span to v.
+func assignsSpan(info *types.Info, nodes []ast.Node, v *types.Var, startSpanMatchers []spanStartMatcher) bool {
+	for _, n := range nodes {
+		if id := getID(n); id == nil || info.ObjectOf(id) != v {
+			continue
+		}
+
+		found := false
+		ast.Inspect(n, func(n ast.Node) bool {
+			if _, ok := isSpanStart(info, n, startSpanMatchers); ok {
+				found = true
 			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
 
-			// Recur
-			if ret := getErrorReturn(pass, search(b.Succs)); ret != nil {
-				return ret
+// getDefBlock returns the block of the CFG that contains stmt, along with the
+// statements of that block after stmt.
+func getDefBlock(g *cfg.CFG, stmt ast.Node) (*cfg.Block, []ast.Node) {
+	for _, b := range g.Blocks {
+		for i, n := range b.Nodes {
+			if n == stmt {
+				return b, b.Nodes[i+1:]
 			}
 		}
-		return nil
 	}
+	return nil, nil
+}
+
+// dominates reports whether before runs ahead of after on every path through root that
+// reaches after: the statement containing before is followed, in the same statement list, by
+// one containing after, and before is not in a nested block, closure, defer or go statement
+// of its statement, where it may not run. This approximates dominance in the CFG, ignoring
+// goto.
+func dominates(root, before, after ast.Node) bool {
+	contains := func(outer, inner ast.Node) bool {
+		return outer.Pos() <= inner.Pos() && inner.End() <= outer.End()
+	}
+	isConditional := func(stmt ast.Stmt) bool {
+		conditional := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause, *ast.FuncLit, *ast.DeferStmt, *ast.GoStmt:
+				conditional = conditional || n != ast.Node(stmt) && contains(n, before)
+			}
+			return !conditional
+		})
+		return conditional
+	}
+
+	found := false
+	ast.Inspect(root, func(n ast.Node) bool {
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+
+		for i, stmt := range list {
+			if !contains(stmt, before) || isConditional(stmt) {
+				continue
+			}
+			for _, later := range list[i+1:] {
+				found = found || contains(later, after)
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// splitTerminalCall returns the nodes before the first one containing a call to a function
+// matching terminalCallSig, along with that call. If there is no such call, nodes is returned as is.
+func splitTerminalCall(info *types.Info, nodes []ast.Node, terminalCallSig *regexp.Regexp) ([]ast.Node, *ast.CallExpr) {
+	if terminalCallSig == nil {
+		return nodes, nil
+	}
+
+	for i, n := range nodes {
+		var terminal *ast.CallExpr
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false // calls in nested functions are not made here
+			case *ast.CallExpr:
+				if obj := calleeObject(info, n); obj != nil && terminalCallSig.MatchString(obj.String()) {
+					terminal = n
+				}
+			}
+			return terminal == nil
+		})
+
+		if terminal != nil {
+			return nodes[:i], terminal
+		}
+	}
+
+	return nodes, nil
+}
+
+// calleeObject returns the object of the function or method called by call, if any.
+func calleeObject(info *types.Info, call *ast.CallExpr) types.Object {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return info.ObjectOf(fun)
+	case *ast.SelectorExpr:
+		return info.ObjectOf(fun.Sel)
+	}
+	return nil
+}
+
+// calleeName returns the name of the function or method called by call.
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	if obj := calleeObject(info, call); obj != nil {
+		return obj.Name()
+	}
+	return "function"
+}
+
+// recordingSuccs returns the successors of b, skipping the branch of an
+// `if span.IsRecording()` guard on which the span is not recording. Calls to a
+// span that is not recording have no effect, so they are not required there.
+func recordingSuccs(b *cfg.Block, sv spanVar) []*cfg.Block {
+	if len(b.Nodes) == 0 || len(b.Succs) != 2 {
+		return b.Succs
+	}
+
+	cond, ok := b.Nodes[len(b.Nodes)-1].(ast.Expr)
+	if !ok {
+		return b.Succs
+	}
+
+	negated := false
+	if unary, ok := ast.Unparen(cond).(*ast.UnaryExpr); ok && unary.Op == token.NOT {
+		negated = true
+		cond = unary.X
+	}
+
+	call, ok := ast.Unparen(cond).(*ast.CallExpr)
+	if !ok {
+		return b.Succs
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "IsRecording" {
+		return b.Succs
+	}
+
+	id, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok || id.Obj == nil || id.Obj.Decl != sv.id.Obj.Decl {
+		return b.Succs
+	}
+
+	if negated {
+		return b.Succs[1:] // the then branch is not recording
+	}
+
+	return b.Succs[:1] // the else branch is not recording
+}
 
-	return search(defBlock.Succs)
+// leavesScope reports whether b is the block after an if, for, range, switch or
+// select statement that declares the span variable v, eg in its init statement
+//
+//	if ctx, span := tracer.Start(ctx, "op"); cond { ... }
+//
+// or one of its branches. The span is out of scope from b on, so can no longer be ended.
+func leavesScope(b *cfg.Block, v *types.Var) bool {
+	switch b.Kind {
+	case cfg.KindIfDone, cfg.KindForDone, cfg.KindRangeDone, cfg.KindSwitchDone, cfg.KindSelectDone:
+		return b.Stmt != nil && b.Stmt.Pos() <= v.Pos() && v.Pos() < b.Stmt.End()
+	}
+	return false
 }
 
 var nestedBlockTypes = map[cfg.BlockKind]struct{}{
@@ -350,12 +1663,12 @@ var nestedBlockTypes = map[cfg.BlockKind]struct{}{
 	cfg.KindSwitchNextCase:  {},
 }
 
-// usesCall reports whether stmts contain a use of the selName call on variable v.
+// usesCall reports whether stmts contain a use of any of the selNames calls on variable v.
 func usesCall(
 	pass *analysis.Pass,
 	stmts []ast.Node,
 	sv spanVar,
-	selName string,
+	selNames []string,
 	ignoreCheckSig *regexp.Regexp,
 	startSpanMatchers []spanStartMatcher,
 	depth int,
@@ -375,12 +1688,32 @@ func usesCall(
 				if len(stack) > 0 {
 					g := cfgs.FuncLit(n)
 					if g != nil && len(g.Blocks) > 0 {
-						return usesCall(pass, g.Blocks[0].Nodes, sv, selName, ignoreCheckSig, startSpanMatchers, depth+1)
+						return usesCall(pass, g.Blocks[0].Nodes, sv, selNames, ignoreCheckSig, startSpanMatchers, depth+1)
 					}
 
+					return false
+				}
+			case *ast.ReturnStmt:
+				// Returning the span hands it to the caller to end.
+				if sv.returns[n] && slices.Contains(selNames, "End") {
+					found = true
 					return false
 				}
 			case *ast.CallExpr:
+				// A call to an end function, eg FinishSpan(ctx, span), ends the span.
+				if sv.endCalls[n] && slices.Contains(selNames, "End") {
+					found = true
+					return false
+				}
+
+				// A call in a defer to a deferred end function ends the span, and if it is
+				// passed a pointer to an error, eg `defer finishSpan(span, &err)`, annotates it
+				// with the error returned on any path too.
+				if errPtr, ok := sv.deferredEndCalls[n]; ok && (errPtr || slices.Contains(selNames, "End")) {
+					found = true
+					return false
+				}
+
 				if ident, ok := n.Fun.(*ast.Ident); ok {
 					fnSig := pass.TypesInfo.ObjectOf(ident).String()
 					if ignoreCheckSig != nil && ignoreCheckSig.MatchString(fnSig) {
@@ -404,7 +1737,7 @@ func usesCall(
 							pass,
 							b.Nodes,
 							sv,
-							selName,
+							selNames,
 							ignoreCheckSig,
 							startSpanMatchers,
 							depth+1,
@@ -425,18 +1758,21 @@ func usesCall(
 
 			// Check whether the span was assigned over top of its old value.
 			_, isStart := isSpanStart(pass.TypesInfo, n, startSpanMatchers)
-			if isStart {
-				if id := getID(stack[len(stack)-3]); id != nil && id.Obj.Decl == sv.id.Obj.Decl {
+			if i := getCallIndex(stack); isStart && i >= 0 {
+				if id := getID(getStartStmt(pass.TypesInfo, stack[:i])); id != nil && id.Obj.Decl == sv.id.Obj.Decl {
 					reAssigned = true
 					return false
 				}
 			}
 
-			if n, ok := n.(*ast.SelectorExpr); ok {
+			if n, ok := n.(*ast.SelectorExpr); ok && !sv.endValues[n] {
 				// Selector (End, SetStatus, RecordError) hit.
-				if n.Sel.Name == selName {
-					id, ok := n.X.(*ast.Ident)
-					found = ok && id.Obj != nil && id.Obj.Decl == sv.id.Obj.Decl
+				if slices.Contains(selNames, n.Sel.Name) {
+					id, ok := ast.Unparen(n.X).(*ast.Ident)
+					found = ok && (id.Obj != nil && id.Obj.Decl == sv.id.Obj.Decl || sv.isAlias(pass.TypesInfo, id))
+					if !found {
+						found = sv.isRef(pass.TypesInfo, n.X) || sv.isChained(pass.TypesInfo, n.X)
+					}
 				}
 
 				// Check if an ignore signature matches.
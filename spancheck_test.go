@@ -1,9 +1,22 @@
 package spancheck_test
 
 import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/jjti/go-spancheck"
 )
@@ -11,39 +24,627 @@ import (
 func Test(t *testing.T) {
 	t.Parallel()
 
-	type configFactory func() *spancheck.Config
+	errorChecks := []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck, spancheck.RecordErrorCheck}
 
-	for dir, configFactory := range map[string]configFactory{
-		"base": spancheck.NewDefaultConfig,
-		"disableerrorchecks": func() *spancheck.Config {
+	for _, tc := range []struct {
+		// dir is a module in testdata, or a package of the testdata/checks module.
+		dir string
+		// checks are enabled instead of the default ones, if set.
+		checks []spancheck.Check
+		// flags are parsed by the analyzer, eg "-all-paths".
+		flags []string
+	}{
+		{dir: "base"},
+		{dir: "disableerrorchecks", checks: errorChecks, flags: []string{"-ignore-check-signatures=telemetry.Record,recordErr"}},
+		{dir: "enableall", checks: errorChecks, flags: []string{"-extra-start-span-signatures=util.TestStartTrace:opentelemetry,enableall.testStartTrace:opencensus"}},
+
+		{dir: "allpaths", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck}, flags: []string{"-all-paths"}},
+		{dir: "confidence"},
+		{dir: "deadspan", checks: []spancheck.Check{spancheck.EndCheck, spancheck.DeadSpanCheck}},
+		{dir: "deferorder", checks: []spancheck.Check{spancheck.EndCheck, spancheck.DeferOrderCheck}},
+		{dir: "deferredendfuncs", checks: errorChecks, flags: []string{`-deferred-end-funcs=deferredendfuncs.endSpan,deferredendfuncs.operation\).finishSpan`, "-same-func-end"}},
+		{dir: "deferwithin", flags: []string{"-defer-within=2"}},
+		{dir: "doubleend", checks: []spancheck.Check{spancheck.EndCheck, spancheck.DoubleEndCheck}},
+		{dir: "endbeforegoroutine", checks: []spancheck.Check{spancheck.EndCheck, spancheck.EndBeforeGoroutineCheck}},
+		{dir: "enderror", checks: []spancheck.Check{spancheck.EndCheck, spancheck.EndErrorCheck}, flags: []string{"-extra-start-span-signatures=enderror.Start:opentelemetry"}},
+		{dir: "endfuncs", flags: []string{"-end-funcs=endfuncs.finishSpan", "-same-func-end"}},
+		{dir: "endstyle", checks: []spancheck.Check{spancheck.EndCheck, spancheck.EndStyleCheck}},
+		{dir: "entrypoints", flags: []string{`-entry-points=\.ServeHTTP$,\.Handle[A-Z]`}},
+		{dir: "fluentspans", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck}, flags: []string{"-extra-start-span-signatures=fluentspans.Start:opentelemetry"}},
+		{dir: "ignorebuildtags", flags: []string{"-ignore-build-tags=!production,production"}},
+		{dir: "ignorespanname", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck, spancheck.LoopSpanCheck}, flags: []string{"-ignore-span-name-regex=^(health-check|ping)$"}},
+		{dir: "isrecording", checks: []spancheck.Check{spancheck.EndCheck, spancheck.IsRecordingCheck}, flags: []string{"-cheap-attribute-funcs=isrecording.cheap"}},
+		{dir: "latespan", checks: []spancheck.Check{spancheck.EndCheck, spancheck.LateSpanCheck}},
+		{dir: "lockedend", checks: []spancheck.Check{spancheck.EndCheck, spancheck.LockedEndCheck}},
+		{dir: "loopspan", checks: []spancheck.Check{spancheck.EndCheck, spancheck.LoopSpanCheck}},
+		{dir: "maxfuncnodes", flags: []string{"-max-func-nodes=5"}},
+		{dir: "musthavespan", checks: []spancheck.Check{spancheck.EndCheck, spancheck.MustHaveSpanCheck}, flags: []string{`-must-have-span-funcs=musthavespan\.[A-Z],\)\.[A-Z]`}},
+		{dir: "niltracer", checks: []spancheck.Check{spancheck.EndCheck, spancheck.NilTracerCheck}},
+		{dir: "noend", checks: errorChecks, flags: []string{"-same-func-end"}},
+		{dir: "noreturnfuncs", flags: []string{"-no-return-funcs=noreturnfuncs.abort"}},
+		{dir: "paniconerrorfuncs", checks: errorChecks, flags: []string{"-panic-on-error-funcs=paniconerrorfuncs.must"}},
+		{dir: "rangefunc", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck}},
+		{dir: "recorderrormismatch", checks: []spancheck.Check{spancheck.EndCheck, spancheck.RecordErrorMismatchCheck}},
+		{dir: "recorderrorsetstatus", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck}, flags: []string{"-record-error-satisfies-set-status"}},
+		{dir: "relaxtestfiles", checks: errorChecks, flags: []string{"-relax-test-files"}},
+		{dir: "requestcontext", checks: []spancheck.Check{spancheck.EndCheck, spancheck.RequestContextCheck}},
+		{dir: "returnedcontext", checks: []spancheck.Check{spancheck.EndCheck, spancheck.ReturnedContextCheck}},
+		{dir: "samefuncend", flags: []string{"-same-func-end"}},
+		{dir: "skipmainend", flags: []string{"-skip-main-end"}},
+		{dir: "spanhelper", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SpanHelperCheck}, flags: []string{"-span-helper-pkgs=/obs$"}},
+		{dir: "spantype", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SpanTypeCheck}},
+		{dir: "startmethods", flags: []string{`-extra-start-span-signatures=startmethods.tracer\).(StartSpan|StartSpanFromContext)\(:opentelemetry`}},
+		{dir: "strictrecorderror", checks: []spancheck.Check{spancheck.EndCheck, spancheck.RecordErrorCheck}, flags: []string{"-strict-record-error"}},
+		{dir: "strictrecoverederrors", checks: errorChecks, flags: []string{"-strict-recovered-errors"}},
+		{dir: "strictsetstatuscode", checks: []spancheck.Check{spancheck.EndCheck, spancheck.SetStatusCheck}, flags: []string{"-strict-set-status-code"}},
+		{dir: "tracername", checks: []spancheck.Check{spancheck.EndCheck, spancheck.TracerNameCheck}},
+		{dir: "unusedcontext", checks: []spancheck.Check{spancheck.EndCheck, spancheck.UnusedContextCheck}},
+		{dir: "useafterend", checks: []spancheck.Check{spancheck.EndCheck, spancheck.UseAfterEndCheck}},
+	} {
+		tc := tc
+		t.Run(tc.dir, func(t *testing.T) {
 			cfg := spancheck.NewDefaultConfig()
-			cfg.EnabledChecks = []string{
-				spancheck.EndCheck.String(),
-				spancheck.RecordErrorCheck.String(),
-				spancheck.SetStatusCheck.String(),
+			if tc.checks != nil {
+				cfg.EnabledChecks = nil
+				for _, check := range tc.checks {
+					cfg.EnabledChecks = append(cfg.EnabledChecks, check.String())
+				}
+			}
+			a := spancheck.NewAnalyzerWithConfig(cfg)
+			if err := a.Flags.Parse(tc.flags); err != nil {
+				t.Fatalf("Unexpected error parsing flags=%v: %v", tc.flags, err)
 			}
-			cfg.IgnoreChecksSignaturesSlice = []string{"telemetry.Record", "recordErr"}
 
-			return cfg
+			dir, pattern := fixture(tc.dir)
+			analysistest.Run(t, dir, a, pattern)
+		})
+	}
+}
+
+// fixture returns the directory and package pattern of the fixture name, either a module
+// in testdata or a package of the testdata/checks module.
+func fixture(name string) (string, string) {
+	if _, err := os.Stat(filepath.Join("testdata", name, "go.mod")); err == nil {
+		return filepath.Join("testdata", name), "."
+	}
+	return filepath.Join("testdata", "checks"), "./" + name
+}
+
+func TestCustomChecks(t *testing.T) {
+	t.Parallel()
+
+	config := spancheck.NewDefaultConfig()
+	config.CustomChecks = []spancheck.CustomCheck{
+		func(_ *analysis.Pass, span spancheck.Span, _ *cfg.CFG) *analysis.Diagnostic {
+			if span.Name == "span" {
+				return nil
+			}
+
+			return &analysis.Diagnostic{
+				Pos:     span.Stmt.Pos(),
+				Message: fmt.Sprintf("%s should be named span", span.Name),
+			}
 		},
-		"enableall": func() *spancheck.Config {
-			cfg := spancheck.NewDefaultConfig()
-			cfg.EnabledChecks = []string{
-				spancheck.EndCheck.String(),
-				spancheck.RecordErrorCheck.String(),
-				spancheck.SetStatusCheck.String(),
+		func(_ *analysis.Pass, span spancheck.Span, _ *cfg.CFG) *analysis.Diagnostic {
+			if len(span.Aliases) == 0 {
+				return nil
 			}
-			cfg.StartSpanMatchersSlice = append(cfg.StartSpanMatchersSlice,
-				"util.TestStartTrace:opentelemetry",
-				"enableall.testStartTrace:opencensus",
-			)
 
-			return cfg
+			return &analysis.Diagnostic{
+				Pos:     span.DefPos,
+				Message: fmt.Sprintf("%s %s is aliased by %s", span.Type, span.Name, span.Aliases[0].Name()),
+			}
 		},
+	}
+
+	analysistest.Run(t, "testdata/checks", spancheck.NewAnalyzerWithConfig(config), "./customchecks")
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	a := spancheck.NewAnalyzer()
+	if err := a.Flags.Parse([]string{
+		"-checks", "end,record-error,set-status",
+		"-ignore-check-signatures", "telemetry.Record,recordErr",
+	}); err != nil {
+		t.Fatalf("Unexpected error parsing flags: %v", err)
+	}
+
+	analysistest.Run(t, "testdata/disableerrorchecks", a)
+}
+
+func TestFlagPrefix(t *testing.T) {
+	t.Parallel()
+
+	cfg := spancheck.NewDefaultConfig()
+	cfg.FlagPrefix = "spancheck."
+	a := spancheck.NewAnalyzerWithConfig(cfg)
+
+	// Merge the analyzer's flags into a driver's flag set that has a flag of the same name.
+	fs := flag.NewFlagSet("driver", flag.ContinueOnError)
+	fs.String("checks", "", "driver flag")
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		if fs.Lookup(f.Name) != nil {
+			t.Fatalf("Unexpected flag name collision=%s", f.Name)
+		}
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	if err := fs.Parse([]string{"-checks", "foo", "-spancheck.checks", "end,set-status"}); err != nil {
+		t.Fatalf("Unexpected error parsing flags: %v", err)
+	}
+
+	if want := []string{"end", "set-status"}; !slices.Equal(cfg.EnabledChecks, want) {
+		t.Fatalf("Unexpected checks=%v, want=%v", cfg.EnabledChecks, want)
+	}
+}
+
+// discardTesting is an analysistest.Testing that ignores errors, eg the ones for
+// expected diagnostics that are not reported because they go to a ReportFunc.
+type discardTesting struct{}
+
+func (discardTesting) Errorf(string, ...interface{}) {}
+
+func TestReportFunc(t *testing.T) {
+	t.Parallel()
+
+	var want []string
+	for _, res := range analysistest.Run(t, "testdata/base", spancheck.NewAnalyzerWithConfig(spancheck.NewDefaultConfig())) {
+		for _, d := range res.Diagnostics {
+			want = append(want, fmt.Sprintf("%s: %s", res.Pass.Fset.Position(d.Pos), d.Message))
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	cfg := spancheck.NewDefaultConfig()
+	cfg.ReportFunc = func(f spancheck.Finding) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if f.Check != spancheck.EndCheck.String() {
+			t.Errorf("Unexpected check=%s, want=%s", f.Check, spancheck.EndCheck)
+		}
+		got = append(got, fmt.Sprintf("%s: %s", f.Position, f.Message))
+	}
+
+	for _, res := range analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg)) {
+		if len(res.Diagnostics) > 0 {
+			t.Errorf("Unexpected diagnostics=%d reported to the pass, want=0", len(res.Diagnostics))
+		}
+	}
+
+	slices.Sort(want)
+	slices.Sort(got)
+	if len(want) == 0 || !slices.Equal(got, want) {
+		t.Fatalf("Unexpected findings=%v, want=%v", got, want)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	var want []string
+	for _, res := range analysistest.Run(t, "testdata/base", spancheck.NewAnalyzerWithConfig(spancheck.NewDefaultConfig())) {
+		for _, d := range res.Diagnostics {
+			want = append(want, fmt.Sprintf("%s: %s", res.Pass.Fset.Position(d.Pos), d.Message))
+		}
+	}
+
+	cfg := spancheck.NewDefaultConfig()
+	pkg := loadBase(t)
+	findings, err := spancheck.Analyze(cfg, pkg.Fset, pkg.Types, pkg.TypesInfo, pkg.Syntax)
+	if err != nil {
+		t.Fatalf("Unexpected error analyzing package: %v", err)
+	}
+	if cfg.ReportFunc != nil {
+		t.Errorf("Unexpected ReportFunc set on the config passed to Analyze")
+	}
+
+	var got []string
+	for i, f := range findings {
+		if i > 0 && f.Pos < findings[i-1].Pos {
+			t.Errorf("Unexpected order of findings, %s is before %s", findings[i-1].Position, f.Position)
+		}
+		got = append(got, fmt.Sprintf("%s: %s", f.Position, f.Message))
+	}
+
+	slices.Sort(want)
+	slices.Sort(got)
+	if len(want) == 0 || !slices.Equal(got, want) {
+		t.Fatalf("Unexpected findings=%v, want=%v", got, want)
+	}
+}
+
+// TestAnalyze_checksFlag cannot be parallel, since it sets environment variables.
+func TestAnalyze_checksFlag(t *testing.T) {
+	t.Setenv("SPANCHECK_END", "0")
+	pkg := loadBase(t)
+
+	for _, tc := range []struct {
+		flags []string
+		want  bool
+	}{
+		{want: false},
+		{flags: []string{"-checks", "end"}, want: true},
 	} {
-		dir := dir
-		t.Run(dir, func(t *testing.T) {
-			analysistest.Run(t, "testdata/"+dir, spancheck.NewAnalyzerWithConfig(configFactory()))
-		})
+		cfg := spancheck.NewDefaultConfig()
+		if err := spancheck.NewAnalyzerWithConfig(cfg).Flags.Parse(tc.flags); err != nil {
+			t.Fatalf("Unexpected error parsing flags: %v", err)
+		}
+
+		findings, err := spancheck.Analyze(cfg, pkg.Fset, pkg.Types, pkg.TypesInfo, pkg.Syntax)
+		if err != nil {
+			t.Fatalf("Unexpected error analyzing package: %v", err)
+		}
+		if got := len(findings) > 0; got != tc.want {
+			t.Errorf("Unexpected findings=%d with flags=%v and SPANCHECK_END=0, want findings=%t", len(findings), tc.flags, tc.want)
+		}
+	}
+}
+
+// loadBase loads and type-checks the package in testdata/base.
+func loadBase(t *testing.T) *packages.Package {
+	t.Helper()
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  filepath.Join("testdata", "base"),
+	}, ".")
+	if err != nil {
+		t.Fatalf("Unexpected error loading package: %v", err)
+	}
+	if len(pkgs) != 1 || len(pkgs[0].Errors) > 0 {
+		t.Fatalf("Unexpected packages=%v", pkgs)
+	}
+	return pkgs[0]
+}
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	cfg := spancheck.NewDefaultConfig()
+	cfg.Explain = true
+	cfg.ExplainOutput = &out
+
+	analysistest.Run(t, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg))
+
+	for _, want := range []string{
+		"base.go:29:2: span: [end] span.End is not called on path Body@L29 to return at line 31",
+		"[end] span.End is called on all paths",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Missing explanation=%q, got=%s", want, out.String())
+		}
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		findings []spancheck.Finding
+	)
+	cfg := spancheck.NewDefaultConfig()
+	cfg.ReportFunc = func(f spancheck.Finding) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		findings = append(findings, f)
+	}
+
+	analysistest.Run(discardTesting{}, "testdata/checks", spancheck.NewAnalyzerWithConfig(cfg), "./confidence")
+
+	slices.SortFunc(findings, func(a, b spancheck.Finding) int { return int(a.Pos - b.Pos) })
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Confidence)
+	}
+
+	want := []string{
+		spancheck.ConfidenceHigh, spancheck.ConfidenceHigh,
+		spancheck.ConfidenceHigh, spancheck.ConfidenceHigh,
+		spancheck.ConfidenceMedium, spancheck.ConfidenceMedium,
+		spancheck.ConfidenceMedium, spancheck.ConfidenceMedium,
+		spancheck.ConfidenceLow, spancheck.ConfidenceLow,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Unexpected confidences=%v, want=%v", got, want)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	t.Parallel()
+
+	cfg := spancheck.NewDefaultConfig()
+	cfg.EnabledChecks = []string{
+		spancheck.EndCheck.String(),
+		spancheck.SetStatusCheck.String(),
+		spancheck.RecordErrorCheck.String(),
+	}
+
+	got := make(map[string]bool)
+	for _, res := range analysistest.Run(discardTesting{}, "testdata/enableall", spancheck.NewAnalyzerWithConfig(cfg)) {
+		for _, d := range res.Diagnostics {
+			got[d.Category] = true
+		}
+	}
+
+	for _, want := range cfg.EnabledChecks {
+		if !got[want] {
+			t.Errorf("Missing diagnostics with category=%s, got=%v", want, got)
+		}
+	}
+	if len(got) != len(cfg.EnabledChecks) {
+		t.Errorf("Unexpected categories=%v, want=%v", got, cfg.EnabledChecks)
+	}
+}
+
+func TestNoChecks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cfg := spancheck.NewDefaultConfig()
+	cfg.EnabledChecks = []string{"ends"}
+	cfg.Logger = log.New(&buf, "", 0)
+
+	for _, res := range analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg)) {
+		if len(res.Diagnostics) > 0 {
+			t.Errorf("Unexpected diagnostics=%d with no checks enabled, want=0", len(res.Diagnostics))
+		}
+	}
+
+	if got := buf.String(); !strings.Contains(got, `no checks are enabled, so nothing is reported. checks "ends"`) {
+		t.Errorf("Unexpected warning=%q", got)
+	}
+}
+
+func TestExcludePkgs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		excludePkgs []string
+		want        bool
+	}{
+		{excludePkgs: []string{`/testdata/base$`}, want: false},
+		{excludePkgs: []string{`/testdata/other$`, `^github\.com/jjti/go-spancheck/testdata/`}, want: false},
+		{excludePkgs: []string{`/testdata/other$`}, want: true},
+	} {
+		cfg := spancheck.NewDefaultConfig()
+		cfg.ExcludePkgsSlice = tc.excludePkgs
+
+		got := false
+		for _, res := range analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg)) {
+			got = got || len(res.Diagnostics) > 0
+		}
+		if got != tc.want {
+			t.Errorf("Unexpected diagnostics=%t with exclude-pkgs=%v, want=%t", got, tc.excludePkgs, tc.want)
+		}
+	}
+}
+
+func TestTracerNameTemplate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		template string
+		want     int
+	}{
+		{template: "", want: 2},
+		{template: "{{.Path}}", want: 2},
+		{template: "{{.Name}}", want: 3},
+		{template: "foo", want: 3},
+		{template: "{{.Path", want: 2}, // invalid, falls back to the default
+	} {
+		cfg := spancheck.NewDefaultConfig()
+		cfg.EnabledChecks = []string{spancheck.TracerNameCheck.String()}
+		cfg.TracerNameTemplate = tc.template
+		cfg.Logger = log.New(io.Discard, "", 0)
+
+		got := 0
+		for _, res := range analysistest.Run(discardTesting{}, "testdata/checks", spancheck.NewAnalyzerWithConfig(cfg), "./tracername") {
+			got += len(res.Diagnostics)
+		}
+		if got != tc.want {
+			t.Errorf("Unexpected diagnostics=%d with tracer-name-template=%q, want=%d", got, tc.template, tc.want)
+		}
+	}
+}
+
+func TestLoopSpanDepth(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		depth int
+		want  int
+	}{
+		{depth: 0, want: 3},
+		{depth: 1, want: 3},
+		{depth: 2, want: 1},
+		{depth: 3, want: 0},
+	} {
+		cfg := spancheck.NewDefaultConfig()
+		cfg.EnabledChecks = []string{spancheck.LoopSpanCheck.String()}
+		cfg.LoopSpanDepth = tc.depth
+
+		got := 0
+		for _, res := range analysistest.Run(discardTesting{}, "testdata/checks", spancheck.NewAnalyzerWithConfig(cfg), "./loopspan") {
+			got += len(res.Diagnostics)
+		}
+		if got != tc.want {
+			t.Errorf("Unexpected diagnostics=%d with loop-span-depth=%d, want=%d", got, tc.depth, tc.want)
+		}
+	}
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	t.Parallel()
+
+	// Fixes are applied to each file and compared to its .golden file.
+	analysistest.RunWithSuggestedFixes(t, "testdata/checks", spancheck.NewAnalyzerWithConfig(spancheck.NewDefaultConfig()), "./suggestedfixes")
+
+	// The golden files are compared after formatting, so check the inserted indentation too.
+	var got []string
+	for _, res := range analysistest.Run(discardTesting{}, "testdata/checks", spancheck.NewAnalyzerWithConfig(spancheck.NewDefaultConfig()), "./suggestedfixes") {
+		for _, d := range res.Diagnostics {
+			for _, fix := range d.SuggestedFixes {
+				for _, edit := range fix.TextEdits {
+					if filepath.Base(res.Pass.Fset.Position(edit.Pos).Filename) == "spaces.go" {
+						got = append(got, string(edit.NewText))
+					}
+				}
+			}
+		}
+	}
+	if want := []string{"\n    defer span.End()"}; !slices.Equal(got, want) {
+		t.Errorf("Unexpected edits=%q of spaces.go, want=%q", got, want)
+	}
+}
+
+func TestSpanHelperPkgs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		pkgs []string
+		want int
+	}{
+		{pkgs: nil, want: 6},
+		{pkgs: []string{"/obs$"}, want: 4},
+		{pkgs: []string{"/obs$", "/testdata/checks/spanhelper$"}, want: 0},
+	} {
+		cfg := spancheck.NewDefaultConfig()
+		cfg.EnabledChecks = []string{spancheck.SpanHelperCheck.String()}
+		cfg.SpanHelperPkgsSlice = tc.pkgs
+		cfg.Logger = log.New(io.Discard, "", 0)
+
+		got := 0
+		for _, res := range analysistest.Run(discardTesting{}, "testdata/checks", spancheck.NewAnalyzerWithConfig(cfg), "./spanhelper/...") {
+			got += len(res.Diagnostics)
+		}
+		if got != tc.want {
+			t.Errorf("Unexpected diagnostics=%d with span-helper-pkgs=%v, want=%d", got, tc.pkgs, tc.want)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	base := spancheck.NewDefaultConfig()
+	base.EnabledChecks = []string{spancheck.EndCheck.String()}
+
+	checks := [][]string{
+		{spancheck.EndCheck.String()},
+		{spancheck.EndCheck.String(), spancheck.SetStatusCheck.String()},
+		{spancheck.EndCheck.String(), spancheck.SetStatusCheck.String(), spancheck.RecordErrorCheck.String()},
+	}
+
+	// Build and run analyzers from clones of base concurrently.
+	got := make([]int, len(checks))
+	var wg sync.WaitGroup
+	for i, enabled := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cfg := base.Clone()
+			cfg.EnabledChecks = append(cfg.EnabledChecks[:0], enabled...)
+			for _, res := range analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg)) {
+				got[i] += len(res.Diagnostics)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 1; i < len(got); i++ {
+		if got[i] <= got[i-1] {
+			t.Errorf("Unexpected diagnostics=%v, want more for each enabled check", got)
+		}
+	}
+	if !slices.Equal(base.EnabledChecks, []string{spancheck.EndCheck.String()}) {
+		t.Errorf("Unexpected base checks=%v after changing clones", base.EnabledChecks)
+	}
+}
+
+func TestNewAnalyzerTwice(t *testing.T) {
+	t.Parallel()
+
+	cfg := spancheck.NewDefaultConfig()
+	a1 := spancheck.NewAnalyzerWithConfig(cfg)
+	a2 := spancheck.NewAnalyzerWithConfig(cfg) // must not register its flags again
+
+	if err := a1.Flags.Set("checks", "end,set-status"); err != nil {
+		t.Fatalf("Unexpected error setting flag: %v", err)
+	}
+
+	var got [2]int
+	for i, a := range []*analysis.Analyzer{a1, a2} {
+		for _, res := range analysistest.Run(discardTesting{}, "testdata/base", a) {
+			got[i] += len(res.Diagnostics)
+		}
+	}
+	if got[0] == 0 || got[0] != got[1] {
+		t.Errorf("Unexpected diagnostics=%v, want the same non-zero count from both analyzers", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	diff := `diff --git a/testdata/base/interface.go b/testdata/base/interface.go
+--- a/testdata/base/interface.go
++++ b/testdata/base/interface.go
+@@ -27,3 +27,4 @@ func (s *otelStarter) StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+ func (s *otelStarter) Do(ctx context.Context) error {
++	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+-	_, span := otel.Tracer("foo").Start(ctx, "bar")
+ 	_ = span
+ 	return nil // want "return can be reached without calling span.End"
+`
+	path := filepath.Join(t.TempDir(), "changes.diff")
+	if err := os.WriteFile(path, []byte(diff), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	cfg := spancheck.NewDefaultConfig()
+	cfg.DiffFile = path
+	cfg.ReportFunc = func(f spancheck.Finding) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got = append(got, fmt.Sprintf("%s:%d: %s", filepath.Base(f.Position.Filename), f.Position.Line, f.Message))
+	}
+
+	analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg))
+
+	want := []string{`interface.go:28: span.End is not called on all paths, possible memory leak (span "bar")`}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Unexpected findings=%v, want=%v", got, want)
+	}
+}
+
+func TestDiff_missingFile(t *testing.T) {
+	t.Parallel()
+
+	cfg := spancheck.NewDefaultConfig()
+	cfg.DiffFile = filepath.Join(t.TempDir(), "missing.diff")
+
+	results := analysistest.Run(discardTesting{}, "testdata/base", spancheck.NewAnalyzerWithConfig(cfg))
+	if len(results) == 0 {
+		t.Fatal("Missing results")
+	}
+	for _, res := range results {
+		if res.Err == nil || !strings.Contains(res.Err.Error(), "failed to read diff") {
+			t.Errorf("Unexpected error=%v, want a failure to read the diff", res.Err)
+		}
 	}
 }
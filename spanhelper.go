@@ -0,0 +1,56 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// spanHelperMethods are the span methods the span-helper check requires to be called through
+// a helper package.
+var spanHelperMethods = []string{"SetStatus", "RecordError", "SetAttributes"}
+
+// reportDirectSpanCalls reports calls to one of spanHelperMethods on an OpenTelemetry or
+// OpenCensus span in a package that is not a helper package, where spans should only be
+// annotated through the helpers.
+func reportDirectSpanCalls(pass *analysis.Pass, config *Config) {
+	if config.spanHelperPkgs != nil && config.spanHelperPkgs.MatchString(pass.Pkg.Path()) {
+		return // helper packages call span methods directly
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !ok || !isSpanMethod(pass.TypesInfo, sel) || !slices.Contains(spanHelperMethods, sel.Sel.Name) {
+				return true
+			}
+
+			config.report(pass, SpanHelperCheck, call, "%s.%s is called directly, use the span helper package instead", types.ExprString(sel.X), sel.Sel.Name)
+			return true
+		})
+	}
+}
+
+// isSpanMethod reports whether sel is a method of an OpenTelemetry or OpenCensus span.
+func isSpanMethod(info *types.Info, sel *ast.SelectorExpr) bool {
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return false
+	}
+
+	fn := s.Obj()
+	if fn.Pkg() == nil {
+		return false
+	}
+	switch fn.Pkg().Path() {
+	case "go.opentelemetry.io/otel/trace", "go.opencensus.io/trace":
+		return true
+	}
+	return false
+}
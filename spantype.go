@@ -0,0 +1,41 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportSpanTypeMismatch reports spans that are ended through an alias of a different type
+// than the span was started as, eg an interface with only an End method. Widening the span
+// drops its other methods, so wrappers relying on them, eg to flush on End, are bypassed.
+func reportSpanTypeMismatch(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	qf := types.RelativeTo(pass.Pkg)
+
+	checked := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		if checked[sv.vr] {
+			continue
+		}
+		checked[sv.vr] = true
+
+		for a := range sv.aliases {
+			if types.Identical(a.Type(), sv.vr.Type()) {
+				continue
+			}
+
+			ast.Inspect(node, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "End" {
+					return true
+				}
+				if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == a {
+					config.report(pass, SpanTypeCheck, sel, "%s.End is called through %s, which has type %s rather than %s",
+						sv.vr.Name(), a.Name(), types.TypeString(a.Type(), qf), types.TypeString(sv.vr.Type(), qf))
+				}
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportNonConstantStatusCodes reports span.SetStatus(code, msg) calls whose code is not a
+// constant, like codes.Error, and so cannot be verified to set an error status. The SetStatus
+// check accepts these calls, this is only reported with StrictSetStatusCode.
+func reportNonConstantStatusCodes(pass *analysis.Pass, config *Config, node ast.Node, spanVars map[*ast.Ident]spanVar) {
+	reported := make(map[*types.Var]bool)
+	for _, sv := range spanVars {
+		if reported[sv.vr] || sv.spanType != spanOpenTelemetry {
+			continue // OpenCensus spans take a status, not a code
+		}
+		reported[sv.vr] = true
+
+		ast.Inspect(node, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "SetStatus" {
+				return true
+			}
+			id, ok := ast.Unparen(sel.X).(*ast.Ident)
+			if !ok || pass.TypesInfo.Uses[id] != sv.vr && !sv.isAlias(pass.TypesInfo, id) {
+				return true
+			}
+
+			if tv, ok := pass.TypesInfo.Types[call.Args[0]]; ok && tv.Value == nil {
+				config.report(pass, SetStatusCheck, call.Args[0], "%s.SetStatus is called with a status code that is not a constant, so it cannot be verified to be an error", id.Name)
+			}
+			return true
+		})
+	}
+}
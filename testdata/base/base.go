@@ -8,6 +8,7 @@ import (
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type testError struct{}
@@ -51,6 +52,29 @@ func _() {
 	fmt.Print(span)
 } // want "return can be reached without calling span.End"
 
+func _() {
+	_, span1 := otel.Tracer("foo").Start(context.Background(), "bar") // want "span1.End is not called on all paths, possible memory leak" "span1.End is never called but span2.End is called 2 times, possible wrong span ended"
+	_, span2 := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Print(span1)
+	defer span2.End()
+	defer span2.End()
+} // want "return can be reached without calling span1.End"
+
+func _(n int) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+
+	switch n {
+	case 0:
+		return errors.New("zero") // want "return can be reached without calling span.End"
+	case 1:
+		return errors.New("one")
+	default:
+		span.End()
+	}
+
+	return nil
+}
+
 // correct
 
 func _() error {
@@ -151,3 +175,202 @@ func _() {
 		}()
 	}()
 } // want "return can be reached without calling span.End"
+
+func _() {
+	_, span1 := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span1.End()
+
+	_, span2 := trace.StartSpan(context.Background(), "bar")
+	defer span2.End()
+}
+
+func _(n int) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+
+	switch n {
+	case 0:
+		span.End()
+		return errors.New("zero")
+	default:
+		span.End()
+	}
+
+	return nil
+}
+
+// Spans started in the init statement of an if, for or switch.
+func _(ok bool) {
+	if _, span := otel.Tracer("foo").Start(context.Background(), "bar"); ok { // want "span.End is not called on all paths, possible memory leak"
+		fmt.Print(span)
+	} else {
+		span.AddEvent("foo")
+	}
+} // want "return can be reached without calling span.End"
+
+func _(ok bool) error {
+	if _, span := otel.Tracer("foo").Start(context.Background(), "bar"); ok { // want "span.End is not called on all paths, possible memory leak"
+		span.End()
+	} else {
+		return errors.New("foo") // want "return can be reached without calling span.End"
+	}
+
+	return nil
+}
+
+func _(ok bool) {
+	if _, span := otel.Tracer("foo").Start(context.Background(), "bar"); ok {
+		span.End()
+	} else {
+		span.End()
+	}
+}
+
+func _(ok bool) {
+	switch _, span := otel.Tracer("foo").Start(context.Background(), "bar"); { // want "span.End is not called on all paths, possible memory leak"
+	case ok:
+		span.End()
+	default:
+		fmt.Print(span)
+	}
+} // want "return can be reached without calling span.End"
+
+func _(ok bool) {
+	switch _, span := otel.Tracer("foo").Start(context.Background(), "bar"); {
+	case ok:
+		span.End()
+	default:
+		span.End()
+	}
+}
+
+func _() {
+	for _, span := otel.Tracer("foo").Start(context.Background(), "bar"); ; { // want "span.End is not called on all paths, possible memory leak"
+		fmt.Print(span)
+		return // want "return can be reached without calling span.End"
+	}
+}
+
+func _() {
+	for _, span := otel.Tracer("foo").Start(context.Background(), "bar"); ; {
+		span.End()
+		return
+	}
+}
+
+// Spans started in a branch are scoped to that branch.
+func _(ok bool) error {
+	if ok {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		fmt.Print(span)
+	} else {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+	}
+
+	return nil // want "return can be reached without calling span.End"
+}
+
+func _(ok bool) error {
+	if ok {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+	} else {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		fmt.Print(span)
+	}
+
+	return nil // want "return can be reached without calling span.End"
+}
+
+func _(ok bool) error {
+	if ok {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		span.End()
+	} else {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+	}
+
+	return nil
+}
+
+func _(n int) {
+	switch n {
+	case 0:
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		fmt.Print(span)
+	case 1:
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		span.End()
+	}
+} // want "return can be reached without calling span.End"
+
+func _(n int) {
+	for i := 0; i < n; i++ {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		if i == 0 {
+			span.End()
+			continue
+		}
+		span.End()
+	}
+}
+
+// Spans can be ended through a variable they are assigned to.
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s := span
+	defer s.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	var ender interface {
+		End(...oteltrace.SpanEndOption)
+	} = span
+	ender.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s1 := span
+	s2 := s1
+	defer func() {
+		s2.End()
+	}()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	s := span
+	s.AddEvent("foo")
+} // want "return can be reached without calling span.End"
+
+// Spans started by a deferred call can never be ended.
+func _() {
+	defer otel.Tracer("foo").Start(context.Background(), "bar") // want "span started in defer is immediately discarded"
+}
+
+func _() {
+	defer trace.StartSpan(context.Background(), "bar") // want "span started in defer is immediately discarded"
+}
+
+type spanHolder struct {
+	ctx   context.Context
+	span  oteltrace.Span
+	spans []oteltrace.Span
+}
+
+// Spans stored in fields or elements can be ended elsewhere.
+func (s *spanHolder) _() {
+	s.ctx, s.span = otel.Tracer("foo").Start(context.Background(), "bar")
+}
+
+func (s *spanHolder) _() {
+	_, s.spans[0] = otel.Tracer("foo").Start(context.Background(), "bar")
+}
+
+func (s *spanHolder) _() {
+	s.ctx, s.span = otel.Tracer("foo").Start(context.Background(), "bar")
+	defer s.span.End()
+}
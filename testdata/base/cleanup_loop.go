@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans appended to a slice are ended by a range loop over the slice.
+func _(ctx context.Context) {
+	var spans []trace.Span
+	defer func() {
+		for _, s := range spans {
+			s.End()
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		var span trace.Span
+		ctx, span = otel.Tracer("foo").Start(ctx, "bar")
+		spans = append(spans, span)
+	}
+}
+
+func _(ctx context.Context) {
+	var spans []trace.Span
+	defer func() {
+		for i := range spans {
+			spans[i].End()
+		}
+	}()
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	spans = append(spans, span)
+}
+
+func _(ctx context.Context) {
+	var spans []trace.Span
+	defer func() {
+		for _, s := range spans {
+			s.AddEvent("foo")
+		}
+	}()
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	spans = append(spans, span)
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	var spans, others []trace.Span
+	defer func() {
+		for _, s := range spans {
+			s.End()
+		}
+	}()
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	others = append(others, span)
+	_ = others
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+var newSpan func() trace.Span
+
+// Spans attached to a context with trace.ContextWithSpan are checked like started spans.
+func _(ctx context.Context) {
+	span := newSpan() // want "span.End is not called on all paths, possible memory leak"
+	ctx = trace.ContextWithSpan(ctx, span)
+	_ = ctx
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	span := newSpan()
+	defer span.End()
+
+	ctx = trace.ContextWithSpan(ctx, span)
+	_ = ctx
+}
+
+func _(ctx context.Context, parent context.Context) {
+	span := trace.SpanFromContext(parent)
+	ctx = trace.ContextWithSpan(ctx, span)
+	_ = ctx
+}
+
+func _(ctx context.Context, span trace.Span) {
+	ctx = trace.ContextWithSpan(ctx, span)
+	_ = ctx
+}
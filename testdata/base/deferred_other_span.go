@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// A deferred End ends the span it was deferred for, not other spans in the function.
+
+// incorrect
+
+func _(ctx context.Context) {
+	ctx, a := otel.Tracer("foo").Start(ctx, "a")
+	defer a.End()
+
+	_, b := otel.Tracer("foo").Start(ctx, "b") // want `b.End is not called on all paths, possible memory leak \(span "b"\)`
+	_ = b
+} // want `return can be reached without calling b.End \(span "b"\)`
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "a")
+	defer span.End()
+
+	_, span = otel.Tracer("foo").Start(ctx, "b") // want `span.End is not called on all paths, possible memory leak \(span "b"\)`
+} // want `return can be reached without calling span.End \(span "b"\)`
+
+func _(ctx context.Context) {
+	ctx, a := otel.Tracer("foo").Start(ctx, "a")
+	defer func() {
+		a.End()
+	}()
+
+	_, b := otel.Tracer("foo").Start(ctx, "b") // want `b.End is not called on all paths, possible memory leak \(span "b"\)`
+	_ = b
+} // want `return can be reached without calling b.End \(span "b"\)`
+
+// correct
+
+func _(ctx context.Context) {
+	ctx, a := otel.Tracer("foo").Start(ctx, "a")
+	defer a.End()
+
+	_, b := otel.Tracer("foo").Start(ctx, "b")
+	defer b.End()
+}
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "a")
+	defer span.End()
+
+	_, span = otel.Tracer("foo").Start(ctx, "b")
+	defer span.End()
+}
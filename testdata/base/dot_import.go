@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	. "go.opencensus.io/trace"
+)
+
+// Span start functions can be dot-imported.
+func _() {
+	_, span := StartSpan(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddAttributes()
+} // want "return can be reached without calling span.End"
+
+func _() {
+	_, span := StartSpan(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() {
+	StartSpan(context.Background(), "bar") // want "span is unassigned, probable memory leak"
+}
+
+func _() {
+	start := StartSpan
+	_, span := start(context.Background(), "bar")
+	defer span.End()
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+
+	. "go.opentelemetry.io/otel/trace"
+)
+
+// Methods of dot-imported tracers are matched by their package.
+func _(tr Tracer) {
+	_, span := tr.Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddEvent("foo")
+} // want "return can be reached without calling span.End"
+
+func _(tr Tracer) {
+	_, span := tr.Start(context.Background(), "bar")
+	defer span.End()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans only ended in an error branch.
+
+// incorrect
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if err := errors.New("foo"); err != nil {
+		span.End()
+		return err
+	}
+
+	return nil // want "return can be reached without calling span.End"
+}
+
+func _(fail bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if fail {
+		span.End()
+	}
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if err := errors.New("foo"); err != nil {
+		span.End()
+		return err
+	}
+
+	span.End()
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+)
+
+type store[K comparable, V any] struct {
+	items map[K]V
+}
+
+// Spans are checked in methods of generic types.
+func (s *store[K, V]) _(ctx context.Context, k K) V {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+
+	return s.items[k] // want "return can be reached without calling span.End"
+}
+
+func (s store[K, V]) _(ctx context.Context, k K) V {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+
+	return s.items[k] // want "return can be reached without calling span.End"
+}
+
+func (s *store[K, V]) _(ctx context.Context, k K) V {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return s.items[k]
+}
+
+func (s store[_, V]) _(ctx context.Context) []V {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	vs := make([]V, 0, len(s.items))
+	for _, v := range s.items {
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func _[T fmt.Stringer](ctx context.Context, t T) string {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+
+	return t.String() // want "return can be reached without calling span.End"
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type starter interface {
+	StartSpan(ctx context.Context, name string) (context.Context, trace.Span)
+	Do(ctx context.Context) error
+}
+
+type otelStarter struct {
+	tracer trace.Tracer
+}
+
+var _ starter = (*otelStarter)(nil)
+
+// Spans returned to satisfy an interface method are ended by the caller.
+func (s *otelStarter) StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	ctx, span := s.tracer.Start(ctx, name)
+	return ctx, span
+}
+
+func (s *otelStarter) Do(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+	return nil // want "return can be reached without calling span.End"
+}
+
+func _(ctx context.Context, s starter) {
+	ctx, span := s.StartSpan(ctx, "bar")
+	defer span.End()
+
+	do := s.Do
+	_ = do(ctx)
+}
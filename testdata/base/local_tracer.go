@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans started by tracers held in local variables, parameters and fields, rather than
+// created inline.
+
+type tracerProvider interface {
+	Tracer(name string, opts ...trace.TracerOption) trace.Tracer
+}
+
+type service struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// incorrect
+
+func _(ctx context.Context) {
+	tracer := otel.GetTracerProvider().Tracer("foo")
+	_, span := tracer.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, provider trace.TracerProvider) {
+	var tracer trace.Tracer = provider.Tracer("foo")
+	_, span := tracer.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, provider tracerProvider) {
+	t := provider.Tracer("foo")
+	_, span := t.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func (s *service) _(ctx context.Context) {
+	_, span := s.tracer.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func (s *service) _(ctx context.Context) {
+	tracer := s.provider.Tracer("foo")
+	_, span := tracer.Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _(ctx context.Context) {
+	tracer := otel.GetTracerProvider().Tracer("foo")
+	_, span := tracer.Start(ctx, "bar")
+	defer span.End()
+}
+
+func (s *service) _(ctx context.Context) {
+	tracer := s.provider.Tracer("foo")
+	_, span := tracer.Start(ctx, "bar")
+	defer span.End()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans ended by calling a variable bound to their End method.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	end := span.End
+	defer end()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	var end = span.End
+	end()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	end := span.End
+	_ = end
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, fail bool) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	end := span.End
+	if fail {
+		return nil // want "return can be reached without calling span.End"
+	}
+	end()
+	return nil
+}
+
+// Passing the End method value to a function hands it the span to end.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer cleanup(span.End)
+}
+
+func cleanup(end func(...trace.SpanEndOption)) {
+	end()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Child spans started from the context of their parent span are checked independently.
+
+// incorrect
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent")
+	defer span.End()
+
+	ctx, child := otel.Tracer("foo").Start(ctx, "child") // want `child.End is not called on all paths, possible memory leak \(span "child"\)`
+	_, _ = ctx, child
+} // want `return can be reached without calling child.End \(span "child"\)`
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent")
+	defer span.End()
+
+	ctx, child := otel.Tracer("foo").Start(ctx, "child") // want `child.End is not called on all paths, possible memory leak \(span "child"\)`
+	if err := fetch(ctx); err != nil {
+		return err // want `return can be reached without calling child.End \(span "child"\)`
+	}
+	child.End()
+
+	return nil
+}
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent") // want `span.End is not called on all paths, possible memory leak \(span "parent"\)`
+	ctx, child := otel.Tracer("foo").Start(ctx, "child")
+	defer child.End()
+
+	if err := fetch(ctx); err != nil {
+		return err // want `return can be reached without calling span.End \(span "parent"\)`
+	}
+	span.End()
+
+	return nil
+}
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent")
+	defer span.End()
+
+	for i := 0; i < 3; i++ {
+		ctx, child := otel.Tracer("foo").Start(ctx, "child") // want `child.End is not called on all paths, possible memory leak \(span "child"\)`
+		_ = fetch(ctx)
+		if i == 0 {
+			continue
+		}
+		child.End()
+	}
+} // want `return can be reached without calling child.End \(span "child"\)`
+
+// correct
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent")
+	defer span.End()
+
+	ctx, child := otel.Tracer("foo").Start(ctx, "child")
+	defer child.End()
+
+	return fetch(ctx)
+}
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "parent")
+	defer span.End()
+
+	childCtx, child := otel.Tracer("foo").Start(ctx, "child")
+	err := fetch(childCtx)
+	child.End()
+	if err != nil {
+		return errors.Join(err, fetch(ctx))
+	}
+
+	return nil
+}
+
+func fetch(ctx context.Context) error { return nil }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans in closures passed to sync.Once.Do are checked in the closure.
+func _(ctx context.Context, once *sync.Once) {
+	once.Do(func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End()
+	})
+}
+
+func _(ctx context.Context, once *sync.Once) {
+	once.Do(func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		if ctx.Err() != nil {
+			return // want "return can be reached without calling span.End"
+		}
+		span.End()
+	})
+}
+
+func _(ctx context.Context) error {
+	var err error
+	load := sync.OnceValue(func() error {
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		if err != nil {
+			return errors.New("foo") // want "return can be reached without calling span.End"
+		}
+		defer span.End()
+		return nil
+	})
+
+	return load()
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans ended with a parenthesized receiver.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer (span).End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	(span).End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	s := span
+	defer (s).End()
+}
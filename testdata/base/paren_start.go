@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans started by a parenthesized call, or a call of a parenthesized method.
+func _(ctx context.Context) {
+	_, span := (otel.Tracer("foo").Start(ctx, "bar")) // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	_, span := (otel.Tracer("foo").Start)(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	_, span := (otel.Tracer("foo").Start(ctx, "bar"))
+	defer span.End()
+}
+
+func _(ctx context.Context) {
+	_, span := (otel.Tracer("foo").Start)(ctx, "bar")
+	defer span.End()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Spans ended through a pointer to them, or a slice, array or map they are stored in.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	p := &span
+	defer (*p).End()
+}
+
+func _(ctx context.Context) {
+	_, span := trace.StartSpan(ctx, "bar")
+	defer (*span).End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	spans := []oteltrace.Span{span}
+	defer spans[0].End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	var spans [1]oteltrace.Span
+	spans[0] = span
+	spans[0].End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	spans := map[string]*oteltrace.Span{"bar": &span}
+	(*spans["bar"]).End()
+}
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_, other := otel.Tracer("foo").Start(ctx, "baz")
+	p := &other
+	(*p).End()
+	_ = span
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans ended in a deferred function that recovers from a panic and re-panics.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer func() {
+		if r := recover(); r != nil {
+			span.End()
+			panic(r)
+		}
+		span.End()
+	}()
+
+	panic("foo")
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer func() {
+		r := recover()
+		span.End()
+		if r != nil {
+			panic(fmt.Sprintf("recovered: %v", r))
+		}
+	}()
+}
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer func() {
+		defer span.End()
+		if r := recover(); r != nil {
+			panic(r)
+		}
+	}()
+
+	return nil
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	defer func() {
+		if r := recover(); r != nil {
+			span.AddEvent("panic")
+			panic(r)
+		}
+	}()
+} // want "return can be reached without calling span.End"
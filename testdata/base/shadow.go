@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+	{
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End() // ends the inner span, not the shadowed outer one
+	}
+} // want "return can be reached without calling span.End"
+
+func _(fail bool) {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+	if fail {
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		span.End() // ends the inner span, not the shadowed outer one
+		return     // want "return can be reached without calling span.End"
+	}
+	span.End()
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+	func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End()
+	}()
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	{
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End()
+	}
+}
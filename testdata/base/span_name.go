@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+const fetchUser = "fetch-user"
+
+// Diagnostics name spans started with a constant name, to tell apart spans in the same function.
+func _(ctx context.Context, name string) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, fetchUser) // want `span.End is not called on all paths, possible memory leak \(span "fetch-user"\)`
+	_, child := otel.Tracer("foo").Start(ctx, name)       // want `child.End is not called on all paths, possible memory leak$`
+	_, _ = span, child
+
+	return errors.New("foo") // want `return can be reached without calling span.End \(span "fetch-user"\)` `return can be reached without calling child.End$`
+}
@@ -0,0 +1,71 @@
+package allpaths
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _(a bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak" "span.SetStatus is not called on all paths"
+	span.AddEvent("foo")
+
+	if a {
+		return errors.New("a") // want "return can be reached without calling span.End" "return can be reached without calling span.SetStatus"
+	} else {
+		return errors.New("b") // want "return can be reached without calling span.End" "return can be reached without calling span.SetStatus"
+	}
+}
+
+func _(n int) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	switch n {
+	case 0:
+		return errors.New("zero") // want "return can be reached without calling span.SetStatus"
+	case 1:
+		span.SetStatus(codes.Error, "one")
+		return errors.New("one")
+	case 2:
+		return errors.New("two") // want "return can be reached without calling span.SetStatus"
+	}
+
+	return nil
+}
+
+func _(n int) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+
+	switch n {
+	case 0:
+		span.SetStatus(codes.Error, "zero")
+		return errors.New("zero") // want "return can be reached without calling span.End"
+	case 1:
+		span.SetStatus(codes.Error, "one")
+		return errors.New("one") // want "return can be reached without calling span.End"
+	default:
+		span.End()
+	}
+
+	return nil
+}
+
+// correct
+
+func _(a bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if a {
+		span.SetStatus(codes.Error, "a")
+		return errors.New("a")
+	} else {
+		span.SetStatus(codes.Error, "b")
+		return errors.New("b")
+	}
+}
@@ -0,0 +1,58 @@
+package confidence
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func keep(span trace.Span) {}
+
+// high: span.End is never called.
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddEvent("foo")
+} // want "return can be reached without calling span.End"
+
+// high: span.End is called on other paths, and missed on a single one.
+func _(fail bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if fail {
+		return errors.New("foo") // want "return can be reached without calling span.End"
+	}
+
+	span.End()
+	return nil
+}
+
+// medium: span.End is called on other paths, and missed on more than one.
+func _(fail, retry bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if fail {
+		return errors.New("foo") // want "return can be reached without calling span.End"
+	}
+	if retry {
+		return errors.New("bar") // only the first path is reported, without AllPaths
+	}
+
+	span.End()
+	return nil
+}
+
+// medium: a deferred call uses the span, and may handle it in a way that isn't followed.
+func _(fail bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	defer func() {
+		if !fail {
+			span.AddEvent("foo")
+		}
+	}()
+} // want "return can be reached without calling span.End"
+
+// low: span escapes, and may be ended elsewhere.
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	keep(span)
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,32 @@
+package customchecks
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _() {
+	_, s := otel.Tracer("foo").Start(context.Background(), "bar") // want "s should be named span"
+	defer s.End()
+}
+
+func _() {
+	_, s := otel.Tracer("foo").Start(context.Background(), "bar") // want "s should be named span" "s.End is not called on all paths, possible memory leak"
+	_ = s
+} // want "return can be reached without calling s.End"
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "opentelemetry span is aliased by s"
+	s := span
+	defer s.End()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
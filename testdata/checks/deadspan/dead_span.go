@@ -0,0 +1,78 @@
+package deadspan
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func fetch(ctx context.Context) error { return nil }
+
+// incorrect
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span is only ended, without using its context or recording anything on it"
+	defer span.End()
+
+	return fetch(ctx)
+}
+
+func _(ctx context.Context) {
+	newCtx, span := otel.Tracer("foo").Start(ctx, "bar") // want `span is only ended, without using its context or recording anything on it \(span "bar"\)`
+	_ = newCtx
+	span.End()
+}
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar") // want "span is only ended"
+	defer span.End()
+}
+
+// correct
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return fetch(ctx)
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("foo", "bar"))
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar", trace.WithAttributes(attribute.String("foo", "bar")))
+	defer span.End()
+}
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if err := fetch(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+func _(ctx context.Context) trace.Span {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	return span // the span is ended by the caller
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	record(span)
+}
+
+func record(span trace.Span) {}
@@ -0,0 +1,29 @@
+package deadspan
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Logging without the span's context does not use the span.
+
+// incorrect
+
+func _(ctx context.Context, logger *slog.Logger) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span is only ended, without using its context or recording anything on it"
+	defer span.End()
+
+	logger.InfoContext(ctx, "started")
+	slog.Info("started")
+}
+
+// correct
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	slog.InfoContext(ctx, "started")
+}
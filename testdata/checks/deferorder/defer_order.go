@@ -0,0 +1,76 @@
+package deferorder
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.SetStatus(codes.Ok, "") // want "span.SetStatus is deferred before span.End, so it runs after the span is ended"
+	defer span.End()
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error()) // want "span.SetStatus is deferred before span.End, so it runs after the span is ended"
+			span.RecordError(err)                    // want "span.RecordError is deferred before span.End, so it runs after the span is ended"
+		}
+	}()
+	defer span.End()
+
+	return errors.New("foo")
+}
+
+func _(ok bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.SetAttributes(attribute.Bool("ok", ok)) // want "span.SetAttributes is deferred before span.End, so it runs after the span is ended"
+	if ok {
+		span.AddEvent("ok")
+	}
+	defer func() {
+		span.End()
+	}()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer span.SetStatus(codes.Ok, "")
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return errors.New("foo")
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	return errors.New("foo")
+}
@@ -0,0 +1,82 @@
+package deferredendfuncs
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type operation struct{}
+
+func (operation) finishSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.SetStatus(codes.Error, (*err).Error())
+		span.RecordError(*err)
+	}
+	span.End()
+}
+
+func endSpan(span trace.Span) {
+	span.End()
+}
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	endSpan(span)                                                    // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer endSpan(span)
+
+	if err := errors.New("foo"); err != nil {
+		return err // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer func() { endSpan(span) }()
+
+	if err := errors.New("foo"); err != nil {
+		return err // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+
+	return nil
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer endSpan(span)
+}
+
+func (o operation) _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer o.finishSpan(span, &err)
+
+	if err = errors.New("foo"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o operation) _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() { o.finishSpan(span, &err) }()
+
+	if err = errors.New("foo"); err != nil {
+		return err
+	}
+
+	return nil
+}
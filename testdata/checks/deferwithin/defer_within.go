@@ -0,0 +1,53 @@
+package deferwithin
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Println("1")
+	fmt.Println("2")
+	defer span.End() // want "span.End is deferred 3 statements after the span is started, more than defer-within 2"
+}
+
+func _(fail bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if fail {
+		defer span.End() // want "span.End is not deferred in the block the span is started in"
+		return
+	}
+	defer span.End()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Println("1")
+	defer span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Println("1")
+	fmt.Println("2")
+	span.End() // not deferred
+}
+
+func _(fail bool) {
+	if fail {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+	}
+}
@@ -0,0 +1,57 @@
+package doubleend
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	span.End() // want "span.End is called, but is also deferred on line 14, so the span is ended twice"
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		span.End()
+	}()
+
+	if err := errors.New("foo"); err != nil {
+		span.End() // want "span.End is called, but is also deferred on line 21, so the span is ended twice"
+		return err
+	}
+
+	return nil
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if err := errors.New("foo"); err != nil {
+		span.End()
+		return err
+	}
+	defer span.End()
+
+	return nil
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	_, span = otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+}
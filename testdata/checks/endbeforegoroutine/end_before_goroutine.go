@@ -0,0 +1,67 @@
+package endbeforegoroutine
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+)
+
+func work(ctx context.Context) {}
+
+// incorrect
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	go work(ctx)
+	span.End() // want "span ended before goroutine using its context completes"
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End() // want "span ended before goroutine using its context completes"
+
+	go func() {
+		work(ctx)
+	}()
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() { // want "span ended before goroutine using its context completes"
+		span.End()
+	}()
+
+	if true {
+		go work(ctx)
+	}
+}
+
+// correct
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		work(ctx)
+	}()
+	wg.Wait()
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	go work(context.Background())
+	work(ctx)
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+	go work(ctx)
+}
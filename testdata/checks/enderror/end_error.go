@@ -0,0 +1,78 @@
+package enderror
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span wraps an OpenTelemetry span, and returns an error from End, eg if it fails to flush.
+type Span struct {
+	span trace.Span
+}
+
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	ctx, span := otel.Tracer("foo").Start(ctx, name)
+	return ctx, &Span{span: span}
+}
+
+func (s *Span) End() error {
+	s.span.End()
+	return nil
+}
+
+// incorrect
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	defer span.End() // want "error returned by span.End is not checked"
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	s := span
+	s.End() // want "error returned by span.End is not checked"
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	defer func() {
+		span.End() // want "error returned by span.End is not checked"
+	}()
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	_ = span.End()
+
+	_, span = Start(ctx, "baz")
+	span.End() // want "error returned by span.End is not checked"
+}
+
+// correct
+
+func _(ctx context.Context) error {
+	_, span := Start(ctx, "bar")
+	return span.End()
+}
+
+func _(ctx context.Context) (err error) {
+	_, span := Start(ctx, "bar")
+	defer func() {
+		if endErr := span.End(); endErr != nil && err == nil {
+			err = endErr
+		}
+	}()
+	return nil
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	_ = span.End()
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End() // End returns nothing
+}
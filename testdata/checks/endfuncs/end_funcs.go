@@ -0,0 +1,55 @@
+package endfuncs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func finishSpan(_ context.Context, span trace.Span) {
+	span.End()
+}
+
+func keepSpan(_ context.Context, span trace.Span) {}
+
+// incorrect
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	keepSpan(ctx, span)                                                // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+func _(fail bool) {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if fail {
+		return // want "return can be reached without calling span.End"
+	}
+
+	finishSpan(ctx, span)
+}
+
+// correct
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	finishSpan(ctx, span)
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer finishSpan(ctx, span)
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		finishSpan(ctx, span)
+	}()
+}
+
+func _() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s := span
+	finishSpan(ctx, s)
+}
@@ -0,0 +1,20 @@
+package endstyle
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() {
+	f := func() {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+	}
+	f()
+}
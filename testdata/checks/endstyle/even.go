@@ -0,0 +1,23 @@
+package endstyle
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End() // want "span.End is called directly, but other spans in this file defer it"
+}
+
+//spancheck:disable end-style
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+}
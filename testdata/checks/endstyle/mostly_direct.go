@@ -0,0 +1,29 @@
+package endstyle
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if err := errors.New("foo"); err != nil {
+		span.End()
+		return err
+	}
+
+	span.End()
+	return nil
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End() // want "span.End is deferred, but other spans in this file call End directly"
+}
@@ -0,0 +1,34 @@
+package endstyle
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+// Ending one span directly on several paths does not make direct calls the majority.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if err := errors.New("foo"); err != nil {
+		span.End() // want "span.End is called directly, but other spans in this file defer it"
+		return err
+	}
+	if err := errors.New("baz"); err != nil {
+		span.End() // want "span.End is called directly, but other spans in this file defer it"
+		return err
+	}
+
+	span.End() // want "span.End is called directly, but other spans in this file defer it"
+	return nil
+}
@@ -0,0 +1,70 @@
+package entrypoints
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+)
+
+type server struct{}
+
+// checked
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, span := otel.Tracer("foo").Start(r.Context(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+
+	s.handle(r.Context())
+	go func() {
+		_, span := otel.Tracer("foo").Start(r.Context(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		_ = span
+	}() // want "return can be reached without calling span.End"
+} // want "return can be reached without calling span.End"
+
+func (s *server) handle(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+
+	helper(ctx)
+} // want "return can be reached without calling span.End"
+
+func helper(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func HandleFoo(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func register(mux *http.ServeMux) {
+	mux.HandleFunc("/bar", handleBar)
+}
+
+func HandleRegister(mux *http.ServeMux) {
+	register(mux)
+}
+
+func handleBar(w http.ResponseWriter, r *http.Request) {
+	_, span := otel.Tracer("foo").Start(r.Context(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// not checked
+
+func background(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	_ = span
+}
+
+func handleBaz(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	_ = span
+}
+
+var _ = func(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	_ = span
+}
@@ -0,0 +1,83 @@
+package fluentspans
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span wraps an OpenTelemetry span with a fluent API, whose methods return the span.
+type Span struct {
+	span trace.Span
+}
+
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	ctx, span := otel.Tracer("foo").Start(ctx, name)
+	return ctx, &Span{span: span}
+}
+
+func (s *Span) SetAttr(key, value string) *Span {
+	s.span.SetAttributes(attribute.String(key, value))
+	return s
+}
+
+func (s *Span) SetStatus(code codes.Code, msg string) *Span {
+	s.span.SetStatus(code, msg)
+	return s
+}
+
+func (s *Span) End() {
+	s.span.End()
+}
+
+// correct
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	span.SetAttr("k", "v").End()
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	defer span.SetAttr("k", "v").SetAttr("k2", "v2").End()
+}
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar")
+	s := span
+	(s.SetAttr("k", "v")).End()
+}
+
+func _(ctx context.Context, err error) error {
+	_, span := Start(ctx, "bar")
+	defer span.End()
+
+	if err != nil {
+		span.SetAttr("k", "v").SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// incorrect
+
+func _(ctx context.Context) {
+	_, span := Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_, other := Start(ctx, "baz")
+	other.SetAttr("k", "v").End()
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, err error) error {
+	_, span := Start(ctx, "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	if err != nil {
+		span.SetAttr("k", "v")
+		return err // want "return can be reached without calling span.SetStatus"
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !debug
+
+package ignorebuildtags
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans in files without an ignored build tag are checked.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,15 @@
+//go:build !race && !production
+
+package ignorebuildtags
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Spans in files with an ignored build tag are not checked.
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	_ = span
+}
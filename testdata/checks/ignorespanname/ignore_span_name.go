@@ -0,0 +1,54 @@
+package ignorespanname
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+const pingName = "ping"
+
+// incorrect
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "fetch") // want `span.End is not called on all paths, possible memory leak \(span "fetch"\)` `span.SetStatus is not called on all paths`
+	_ = span
+
+	return errors.New("foo") // want `return can be reached without calling span.End \(span "fetch"\)` "return can be reached without calling span.SetStatus"
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "health-check-db") // want `span.End is not called on all paths, possible memory leak \(span "health-check-db"\)`
+	_ = span
+} // want `return can be reached without calling span.End \(span "health-check-db"\)`
+
+func _(ctx context.Context, name string) {
+	_, span := otel.Tracer("foo").Start(ctx, name) // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "health-check")
+	_ = span
+
+	return errors.New("foo")
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, pingName)
+	_ = span
+}
+
+func _(ctx context.Context) {
+	otel.Tracer("foo").Start(ctx, "ping")
+}
+
+func _(ctx context.Context) {
+	for i := 0; i < 10; i++ {
+		_, span := otel.Tracer("foo").Start(ctx, "ping")
+		span.End()
+	}
+}
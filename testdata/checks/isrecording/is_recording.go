@@ -0,0 +1,82 @@
+package isrecording
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func expensive() string { return "foo" }
+
+func cheap() string { return "foo" }
+
+// incorrect
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("foo", expensive())) // want "span.SetAttributes computes its arguments with a call to expensive, guard it with span.IsRecording()"
+}
+
+func _(ctx context.Context, n int) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	span.AddEvent("foo", trace.WithAttributes(attribute.String("n", strconv.Itoa(n)))) // want "span.AddEvent computes its arguments with a call to Itoa, guard it with span.IsRecording()"
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if !span.IsRecording() {
+		span.SetAttributes(attribute.String("foo", expensive())) // want "span.SetAttributes computes its arguments with a call to expensive, guard it with span.IsRecording()"
+	}
+}
+
+func _(ctx context.Context, ok bool) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if ok {
+		if !span.IsRecording() {
+			return
+		}
+	}
+	span.SetAttributes(attribute.String("foo", expensive())) // want "span.SetAttributes computes its arguments with a call to expensive, guard it with span.IsRecording()"
+}
+
+// correct
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if span.IsRecording() {
+		span.SetAttributes(attribute.String("foo", expensive()))
+	}
+}
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.String("foo", expensive()))
+}
+
+func _(ctx context.Context, n int) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	err := errors.New("foo")
+	span.SetAttributes(attribute.Int("n", n), attribute.String("err", err.Error()), attribute.String("foo", cheap()))
+	span.AddEvent("foo", trace.WithAttributes(attribute.String("n", string(rune(n)))))
+}
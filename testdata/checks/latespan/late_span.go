@@ -0,0 +1,92 @@
+package latespan
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+)
+
+var errNotFound = errors.New("not found")
+
+func validate(id string) error { return nil }
+
+func fetch(ctx context.Context, id string) (string, error) { return "", nil }
+
+// incorrect
+
+func _(ctx context.Context, id string) error {
+	if err := validate(id); err != nil { // want "operation error occurs before span starts; failures won't be traced"
+		return err
+	}
+
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	_, err := fetch(ctx, id)
+	return err
+}
+
+func _(ctx context.Context, s string) (int, error) {
+	n, err := strconv.Atoi(s) // want "operation error occurs before span starts; failures won't be traced"
+	if err != nil {
+		return 0, err
+	}
+	_ = validate(s)
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return n, nil
+}
+
+func _(ctx context.Context, id string) error {
+	return func() error {
+		if err := validate(id); err != nil { // want "operation error occurs before span starts; failures won't be traced"
+			return err
+		}
+
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End()
+		return nil
+	}()
+}
+
+// correct
+
+func _(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if err := validate(id); err != nil {
+		return err
+	}
+	_, err := fetch(ctx, id)
+	return err
+}
+
+func _(ctx context.Context, id string) error {
+	_ = validate(id)
+	validate(id)
+	defer validate(id)
+	err := error(errNotFound)
+	_ = err
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	return nil
+}
+
+func _(ctx context.Context, id string) {
+	if err := validate(id); err != nil {
+		return // the function does not return an error
+	}
+
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+}
+
+func _(id string) error {
+	return validate(id) // the function does not start a span
+}
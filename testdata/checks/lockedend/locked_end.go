@@ -0,0 +1,86 @@
+package lockedend
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+)
+
+type cache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+type store struct {
+	sync.RWMutex
+}
+
+// incorrect
+
+func (c *cache) _(key string) {
+	c.mu.Lock()
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	c.items[key] = ""
+	span.End() // want "span.End is called while c.mu is locked, span operation inside locked section may block on exporter"
+	c.mu.Unlock()
+}
+
+func (c *cache) _(key string) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer span.End() // want "span.End is called while c.mu is locked, span operation inside locked section may block on exporter"
+
+	c.items[key] = ""
+}
+
+func (s *store) _(fail bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s.RLock()
+	if fail {
+		span.End() // want "span.End is called while s is locked, span operation inside locked section may block on exporter"
+		s.RUnlock()
+		return
+	}
+	s.RUnlock()
+	span.End()
+}
+
+// correct
+
+func (c *cache) _(key string) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End() // runs after the deferred unlock
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = ""
+}
+
+func (c *cache) _(key string) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	c.mu.Lock()
+	defer span.End() // runs at return, after the unlock below
+	c.items[key] = ""
+	c.mu.Unlock()
+}
+
+func (c *cache) _(key string) {
+	c.mu.Lock()
+	c.items[key] = ""
+	c.mu.Unlock()
+
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+}
+
+func _(mu *sync.Mutex, other *sync.Mutex) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	other.Lock()
+	other.Unlock()
+	mu.Lock()
+	mu.Unlock()
+	span.End()
+}
@@ -0,0 +1,80 @@
+package loopspan
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+type item struct{ id string }
+
+// incorrect
+
+func _(ctx context.Context, items []item) {
+	for _, it := range items {
+		_, span := otel.Tracer("foo").Start(ctx, it.id) // want "span started unconditionally in loop may cause high cardinality/volume, consider sampling or batching"
+		span.End()
+	}
+}
+
+func _(ctx context.Context, n int) {
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			continue
+		}
+
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span started unconditionally in loop may cause high cardinality/volume"
+		span.End()
+	}
+}
+
+func _(ctx context.Context, items [][]item) {
+	for _, batch := range items {
+		if len(batch) == 0 {
+			continue
+		}
+		for range batch {
+			_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span started unconditionally in loop may cause high cardinality/volume"
+			span.End()
+		}
+	}
+}
+
+// correct
+
+func _(ctx context.Context, items []item) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "batch")
+	defer span.End()
+
+	for range items {
+		_ = ctx
+	}
+}
+
+func _(ctx context.Context, n int) {
+	for i := 0; i < n; i++ {
+		if i%100 == 0 {
+			_, span := otel.Tracer("foo").Start(ctx, "sampled")
+			span.End()
+		}
+	}
+}
+
+func _(ctx context.Context, events <-chan item) {
+	for {
+		select {
+		case ev := <-events:
+			_, span := otel.Tracer("foo").Start(ctx, ev.id)
+			span.End()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func _(ctx context.Context) {
+	for _, span := otel.Tracer("foo").Start(ctx, "bar"); ; {
+		span.End()
+		return
+	}
+}
@@ -0,0 +1,27 @@
+package maxfuncnodes
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+} // want "return can be reached without calling span.End"
+
+// correct
+
+// Too many nodes to analyze.
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Print(span)
+	fmt.Print(1)
+	fmt.Print(2)
+	fmt.Print(3)
+	fmt.Print(4)
+}
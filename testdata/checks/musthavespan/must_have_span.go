@@ -0,0 +1,50 @@
+package musthavespan
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+type service struct{}
+
+// incorrect
+
+func Task(ctx context.Context) { // want "Task takes a context.Context but does not start a span"
+	_ = ctx
+}
+
+func (s *service) Task(ctx context.Context, n int) error { // want "Task takes a context.Context but does not start a span"
+	_, _ = ctx, n
+	return nil
+}
+
+// correct
+
+func Traced(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+}
+
+func TracedInGoroutine(ctx context.Context) {
+	go func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar")
+		defer span.End()
+	}()
+}
+
+func NoContext(n int) {
+	_ = n
+}
+
+func ContextNotFirst(n int, ctx context.Context) {
+	_, _ = n, ctx
+}
+
+func helper(ctx context.Context) {
+	_ = ctx
+}
+
+func (s *service) helper(ctx context.Context) {
+	_ = ctx
+}
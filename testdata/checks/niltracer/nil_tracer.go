@@ -0,0 +1,105 @@
+package niltracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type service struct {
+	tracer trace.Tracer
+}
+
+var tracer trace.Tracer
+
+// incorrect
+
+func (s *service) _(ctx context.Context) {
+	_, span := s.tracer.Start(ctx, "bar") // want "s.tracer may be nil, check it before calling Start"
+	defer span.End()
+}
+
+func _(ctx context.Context) {
+	_, span := tracer.Start(ctx, "bar") // want "tracer may be nil, check it before calling Start"
+	defer span.End()
+}
+
+func _(ctx context.Context, t trace.Tracer) {
+	_, span := t.Start(ctx, "bar") // want "t may be nil, check it before calling Start"
+	defer span.End()
+}
+
+func (s *service) _(ctx context.Context, ok bool) {
+	if ok {
+		_, span := s.tracer.Start(ctx, "bar") // want "s.tracer may be nil, check it before calling Start"
+		defer span.End()
+	}
+
+	if s.tracer == nil {
+		return
+	}
+}
+
+func (s *service) _(ctx context.Context) {
+	if s.tracer == nil {
+		println("no tracer")
+	}
+
+	_, span := s.tracer.Start(ctx, "bar") // want "s.tracer may be nil, check it before calling Start"
+	defer span.End()
+}
+
+// correct
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+}
+
+func _(ctx context.Context) {
+	t := otel.Tracer("foo")
+	_, span := t.Start(ctx, "bar")
+	defer span.End()
+}
+
+func (s *service) _(ctx context.Context) {
+	if s.tracer != nil {
+		_, span := s.tracer.Start(ctx, "bar")
+		defer span.End()
+	}
+}
+
+func (s *service) _(ctx context.Context) {
+	if s.tracer == nil {
+		return
+	}
+
+	_, span := s.tracer.Start(ctx, "bar")
+	defer span.End()
+}
+
+func _(ctx context.Context, t trace.Tracer) {
+	if t == nil {
+		t = otel.Tracer("foo")
+	}
+
+	_, span := t.Start(ctx, "bar")
+	defer span.End()
+}
+
+func _(ctx context.Context, ok bool) {
+	if tracer != nil && ok {
+		_, span := tracer.Start(ctx, "bar")
+		defer span.End()
+	}
+}
+
+func _(ctx context.Context, t trace.Tracer) {
+	if t == nil {
+		panic("no tracer")
+	}
+
+	_, span := t.Start(ctx, "bar")
+	defer span.End()
+}
@@ -0,0 +1,67 @@
+package noend
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func handOff(span trace.Span) {}
+
+// incorrect
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") //spancheck:noend // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	handOff(span)
+
+	if err := errors.New("foo"); err != nil {
+		return err // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+	return nil
+}
+
+func _(ctx context.Context) {
+	//spancheck:noend
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	handOff(span)                                   // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") //spancheck:noendx // want "span.End is not called on all paths, possible memory leak"
+	handOff(span)                                   // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	ctx, parent := otel.Tracer("foo").Start(ctx, "parent") //spancheck:noend
+	handOff(parent)
+
+	_, span := otel.Tracer("foo").Start(ctx, "child") // want "span.End is not called on all paths, possible memory leak"
+	handOff(span)                                     // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") //spancheck:noend
+	handOff(span)
+}
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") //spancheck:noend handed to the background worker
+	handOff(span)
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func _(ctx context.Context) {
+	var _, span = otel.Tracer("foo").Start(ctx, "bar") //spancheck:noend
+	handOff(span)
+}
@@ -0,0 +1,48 @@
+package noreturnfuncs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// abort is treated as never returning by the analyzer's config.
+func abort(msg string) {
+	print(msg)
+}
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+
+	if true {
+		abort("foo") // want "abort never returns and can be reached without calling span.End"
+	}
+
+	span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddEvent("foo")
+	abort("foo") // want "abort never returns and can be reached without calling span.End"
+	span.End()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if true {
+		abort("foo")
+	}
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+	abort("foo")
+}
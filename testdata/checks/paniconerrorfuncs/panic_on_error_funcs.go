@@ -0,0 +1,71 @@
+package paniconerrorfuncs
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func mustValue[T any](v T, err error) T {
+	must(err)
+	return v
+}
+
+func doThing() error {
+	return errors.New("foo")
+}
+
+func parse() (int, error) {
+	return 0, errors.New("foo")
+}
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	must(doThing()) // want "must panics on error and can be reached without calling span.SetStatus" "must panics on error and can be reached without calling span.RecordError"
+}
+
+func _() int {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	if true {
+		span.RecordError(errors.New("foo"))
+		v := mustValue(parse()) // want "mustValue panics on error and can be reached without calling span.SetStatus"
+		return v
+	}
+
+	return 0
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	err := doThing()
+	span.SetStatus(codes.Error, "foo")
+	span.RecordError(err)
+	must(err)
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	go func() {
+		must(doThing())
+	}()
+}
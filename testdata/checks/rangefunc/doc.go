@@ -0,0 +1,3 @@
+// Package rangefunc has spans in range-over-func iterators, which need go1.23, so the
+// fixture is empty with older toolchains.
+package rangefunc
@@ -0,0 +1,85 @@
+//go:build go1.23
+
+package rangefunc
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		for i := 0; i < 3; i++ {
+			if !yield(i) {
+				return // want "return can be reached without calling span.End"
+			}
+		}
+		span.End()
+	}
+}
+
+func _(seq iter.Seq[int]) {
+	for i := range seq {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+		if i > 0 {
+			break
+		}
+		span.End()
+	}
+} // want "return can be reached without calling span.End"
+
+func _(seq iter.Seq[int]) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	for i := range seq {
+		if i > 0 {
+			return errors.New("foo") // want "return can be reached without calling span.SetStatus"
+		}
+	}
+
+	return nil
+}
+
+// correct
+
+func _() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End()
+
+		for i := 0; i < 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func _(seq iter.Seq[int]) {
+	for range seq {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		defer span.End() // deferred to the end of the enclosing function
+	}
+}
+
+func _(seq iter.Seq2[int, error]) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	for _, err := range seq {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
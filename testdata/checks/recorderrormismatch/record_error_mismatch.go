@@ -0,0 +1,84 @@
+package recorderrormismatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var errNotFound = errors.New("not found")
+
+// incorrect
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		if err2 := errors.New("bar"); err2 != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err) // want "RecordError records a different error than the one returned on this path"
+			return err2
+		}
+	}
+
+	return nil
+}
+
+// correct
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return errNotFound // a sentinel in place of the recorded error
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return fmt.Errorf("bar: %w", err)
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		wrapped := fmt.Errorf("op: %w", err) // built from the recorded error
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return wrapped
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package recorderrorsetstatus
+
+import (
+	"context"
+	"errors"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	if true {
+		span.AddEvent("foo")
+		return errors.New("foo") // want "return can be reached without calling span.SetStatus"
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := trace.StartSpan(context.Background(), "bar") // want "span.SetStatus is not called on all paths"
+	defer span.End()
+
+	if true {
+		return errors.New("foo") // want "return can be reached without calling span.SetStatus"
+	}
+
+	return nil
+}
+
+// correct
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if true {
+		err := errors.New("foo")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if true {
+		span.SetStatus(codes.Error, "foo")
+		return errors.New("foo")
+	}
+
+	return nil
+}
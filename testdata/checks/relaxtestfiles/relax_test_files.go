@@ -0,0 +1,17 @@
+package relaxtestfiles
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Outside test files, the set-status and record-error checks still apply.
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return errors.New("foo") // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
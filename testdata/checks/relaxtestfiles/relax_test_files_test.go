@@ -0,0 +1,34 @@
+package relaxtestfiles
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func TestLeak(t *testing.T) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func helper(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return errors.New("foo")
+}
+
+func TestHelper(t *testing.T) {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := helper(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+}
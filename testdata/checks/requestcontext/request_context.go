@@ -0,0 +1,84 @@
+package requestcontext
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		next.ServeHTTP(w, r) // want `request is passed on without the context of span, use r.WithContext\(ctx\)`
+	})
+}
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		if r.Method == http.MethodGet {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r) // want `request is passed on without the context of span, use r.WithContext\(ctx\)`
+	})
+}
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		next.ServeHTTP(w, r) // want `request is passed on without the context of span, use r.WithContext\(ctx\)`
+		r = r.WithContext(ctx)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// correct
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		r = r.WithContext(ctx)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func _(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+	defer span.End()
+
+	w.Header().Set("foo", "bar")
+	print(ctx)
+}
+
+func _(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r) // before the span starts
+
+		ctx, span := otel.Tracer("foo").Start(r.Context(), "bar")
+		defer span.End()
+
+		r = r.WithContext(ctx)
+		if r.Method == http.MethodGet {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
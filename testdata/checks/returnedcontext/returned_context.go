@@ -0,0 +1,66 @@
+package returnedcontext
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+type key struct{}
+
+// incorrect
+
+func _(ctx context.Context) (context.Context, error) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return ctx, nil // want "ctx is returned but span is ended by a deferred End before the caller can use it"
+}
+
+func _(ctx context.Context) context.Context {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer func() {
+		span.End()
+	}()
+
+	return context.WithValue(ctx, key{}, "bar") // want "ctx is returned but span is ended by a deferred End before the caller can use it"
+}
+
+func _(ctx context.Context, fail bool) (context.Context, error) {
+	spanCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if fail {
+		return ctx, errors.New("fail")
+	}
+
+	return spanCtx, nil // want "spanCtx is returned but span is ended by a deferred End before the caller can use it"
+}
+
+// correct
+
+func _(ctx context.Context) (context.Context, func()) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	return ctx, func() { span.End() }
+}
+
+func _(ctx context.Context) (context.Context, error) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	span.AddEvent("foo")
+	return context.Background(), nil
+}
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	f := func() context.Context {
+		return ctx
+	}
+	_ = f()
+
+	return nil
+}
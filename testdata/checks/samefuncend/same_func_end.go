@@ -0,0 +1,69 @@
+package samefuncend
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type holder struct {
+	span trace.Span
+}
+
+func finish(span trace.Span) {
+	span.End()
+}
+
+// incorrect
+
+func _() trace.Span {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	return span                                                      // want "span must be ended in the function that starts it" "return can be reached without calling span.End"
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	finish(span)                                                     // want "span must be ended in the function that starts it"
+} // want "return can be reached without calling span.End"
+
+func _(h *holder) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	h.span = span // want "span must be ended in the function that starts it"
+}
+
+func _(spans chan trace.Span) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	spans <- span                // want "span must be ended in the function that starts it"
+	_ = holder{span: span}       // want "span must be ended in the function that starts it"
+	_ = []trace.Span{span, span} // want "span must be ended in the function that starts it" "span must be ended in the function that starts it"
+}
+
+func _(h *holder) {
+	var ctx context.Context
+	ctx, h.span = otel.Tracer("foo").Start(context.Background(), "bar") // want "h.span must be ended in the function that starts it"
+	_ = ctx
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	span.AddEvent("foo")
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		span.End()
+	}()
+
+	s := span
+	s.AddEvent("foo")
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// incorrect
+
+func run() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+type server struct{}
+
+func (server) main() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func main() {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "app")
+
+	span.AddEvent("started") // ended at shutdown, by a signal handler
+
+	func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		_ = span
+	}() // want "return can be reached without calling span.End"
+
+	run()
+}
@@ -0,0 +1,13 @@
+// Package obs is the helper package spans are annotated through.
+package obs
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fail records err on span and sets its status to an error.
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
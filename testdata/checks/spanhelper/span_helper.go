@@ -0,0 +1,45 @@
+package spanhelper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jjti/go-spancheck/testdata/checks/spanhelper/obs"
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("k", "v")) // want "span.SetAttributes is called directly, use the span helper package instead"
+
+	err := errors.New("foo")
+	span.RecordError(err)                    // want "span.RecordError is called directly, use the span helper package instead"
+	span.SetStatus(codes.Error, err.Error()) // want "span.SetStatus is called directly, use the span helper package instead"
+	return err
+}
+
+func _(ctx context.Context) {
+	_, span := trace.StartSpan(ctx, "bar")
+	defer span.End()
+
+	span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown}) // want "span.SetStatus is called directly, use the span helper package instead"
+}
+
+// correct
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	err := errors.New("foo")
+	obs.Fail(span, err)
+	span.AddEvent("baz")
+	return err
+}
@@ -0,0 +1,42 @@
+package spantype
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ender interface {
+	End(options ...trace.SpanEndOption)
+}
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	var e ender = span
+	defer e.End() // want "span.End is called through e, which has type ender rather than go.opentelemetry.io/otel/trace.Span"
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s := span
+	var e ender = s
+	e.End() // want "span.End is called through e, which has type ender rather than go.opentelemetry.io/otel/trace.Span"
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	s := span
+	defer s.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	var e ender = span
+	_ = e
+	defer span.End()
+}
@@ -0,0 +1,45 @@
+package startmethods
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer has two methods that start spans, like opentracing's StartSpan and
+// StartSpanFromContext, matched by a single signature.
+type tracer struct{}
+
+func (tracer) StartSpan(name string) trace.Span {
+	_, span := otel.Tracer("foo").Start(context.Background(), name)
+	return span
+}
+
+func (tracer) StartSpanFromContext(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer("foo").Start(ctx, name)
+}
+
+// incorrect
+
+func _(t tracer) {
+	span := t.StartSpan("bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, t tracer) {
+	_, span := t.StartSpanFromContext(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+// correct
+
+func _(t tracer) {
+	span := t.StartSpan("bar")
+	defer span.End()
+}
+
+func _(ctx context.Context, t tracer) {
+	_, span := t.StartSpanFromContext(ctx, "bar")
+	defer span.End()
+}
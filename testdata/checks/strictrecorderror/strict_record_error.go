@@ -0,0 +1,109 @@
+package strictrecorderror
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+)
+
+func a() error { return nil }
+func b() error { return nil }
+
+// incorrect
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.RecordError is not called on all paths"
+	defer span.End()
+
+	if err := a(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := b(); err != nil {
+		return err // want "return can be reached without calling span.RecordError"
+	}
+
+	return errors.New("foo") // want "return can be reached without calling span.RecordError"
+}
+
+func _(n int) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.RecordError is not called on all paths"
+	defer span.End()
+
+	switch n {
+	case 0:
+		err := errors.New("zero")
+		span.RecordError(err)
+		return err
+	case 1:
+		return errors.New("one") // want "return can be reached without calling span.RecordError"
+	}
+
+	for i := 0; i < n; i++ {
+		if err := a(); err != nil {
+			return err // want "return can be reached without calling span.RecordError"
+		}
+	}
+
+	return nil
+}
+
+// correct
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := a(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := b(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	if err := a(); err != nil {
+		return err
+	}
+
+	return b()
+}
+
+func _() error {
+	for i := 0; i < 3; i++ {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		if err := a(); err != nil {
+			span.RecordError(err)
+			span.End()
+			return err
+		}
+		span.End()
+	}
+
+	return errors.New("foo")
+}
+
+func _(ok bool) error {
+	if ok {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		span.End()
+	}
+
+	return errors.New("foo")
+}
@@ -0,0 +1,96 @@
+package strictrecoverederrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r) // want "span.SetStatus is not called when a recovered panic is returned as an error" "span.RecordError is not called when a recovered panic is returned as an error"
+		}
+	}()
+
+	return nil
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			span.SetStatus(codes.Error, "panic")
+			err = fmt.Errorf("panic: %v", r) // want "span.RecordError is not called when a recovered panic is returned as an error"
+		}
+	}()
+
+	return nil
+}
+
+// correct
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return nil
+}
+
+func _() (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("wrapped: %w", err) // no recover, errors are checked at the returns
+		}
+	}()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r) // registered before the span is started
+		}
+	}()
+
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println(r) // the panic is not returned as an error
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package strictsetstatuscode
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _(code codes.Code) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(code, err.Error()) // want "span.SetStatus is called with a status code that is not a constant, so it cannot be verified to be an error"
+		return err
+	}
+
+	return nil
+}
+
+func _(fail bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	code := codes.Ok
+	if fail {
+		code = codes.Error
+	}
+	span.SetStatus(code, "") // want "span.SetStatus is called with a status code that is not a constant, so it cannot be verified to be an error"
+
+	return errors.New("foo")
+}
+
+// correct
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	const code = codes.Error
+
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(code, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package suggestedfixes
+
+import (
+	"context"
+	"errors"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+)
+
+// End is deferred right after spans that are never ended.
+
+func _(ctx context.Context) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddEvent("baz")
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context, fail bool) error {
+	if !fail {
+		return nil
+	}
+
+	ctx, span := trace.StartSpan(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_, _ = ctx, span
+	return errors.New("foo") // want "return can be reached without calling span.End"
+}
+
+func _(ctx context.Context, kind string) {
+	switch kind {
+	case "bar":
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		_ = span
+	}
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	go func() {
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		_ = span
+	}() // want "return can be reached without calling span.End"
+}
+
+// No fix is suggested for spans ended on some paths, started in loops or not started by a
+// statement.
+
+func _(ctx context.Context, fail bool) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	if fail {
+		return errors.New("foo") // want "return can be reached without calling span.End"
+	}
+	span.End()
+	return nil
+}
+
+func _(ctx context.Context, n int) {
+	for i := 0; i < n; i++ {
+		_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+		_ = span
+	}
+} // want "return can be reached without calling span.End"
+
+func _(ctx context.Context) {
+	var _, span = otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,14 @@
+package suggestedfixes
+
+import (
+    "context"
+
+    "go.opentelemetry.io/otel"
+)
+
+// End is deferred with the indentation of the line starting the span, here spaces.
+
+func _(ctx context.Context) {
+    _, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+    span.AddEvent("baz")
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,36 @@
+package tracername
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const name = "github.com/jjti/go-spancheck/testdata/checks/tracername"
+
+// incorrect
+
+var tracer = otel.Tracer("foo") // want `tracer name "foo" does not match "github.com/jjti/go-spancheck/testdata/checks/tracername"`
+
+func _(tp trace.TracerProvider) {
+	_, span := tp.Tracer("tracername").Start(context.Background(), "bar") // want `tracer name "tracername" does not match "github.com/jjti/go-spancheck/testdata/checks/tracername"`
+	defer span.End()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("github.com/jjti/go-spancheck/testdata/checks/tracername").Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _(tp trace.TracerProvider) {
+	_, span := tp.Tracer(name).Start(context.Background(), "bar")
+	defer span.End()
+}
+
+func _(tracerName string) {
+	_, span := otel.Tracer(tracerName).Start(context.Background(), "bar") // not a constant
+	defer span.End()
+}
@@ -0,0 +1,46 @@
+package unusedcontext
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Logging with the context passes it on, so the record is correlated with its span.
+
+// incorrect
+
+func _(ctx context.Context) error {
+	newCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	_ = newCtx
+
+	slog.InfoContext(ctx, "started") // want "child work uses the pre-span context ctx, not newCtx returned by Start"
+	return fetch(ctx)
+}
+
+func _(ctx context.Context, logger *slog.Logger) {
+	spanCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	_ = spanCtx
+
+	logger.ErrorContext(ctx, "failed") // want "child work uses the pre-span context ctx, not spanCtx returned by Start"
+}
+
+// correct
+
+func _(ctx context.Context) {
+	newCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	slog.InfoContext(newCtx, "started")
+	slog.InfoContext(ctx, "the returned context is used")
+}
+
+func _(ctx context.Context, logger *slog.Logger) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	logger.InfoContext(ctx, "started")
+}
@@ -0,0 +1,64 @@
+package unusedcontext
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+func fetch(ctx context.Context) error { return nil }
+
+// incorrect
+
+func _(ctx context.Context) error {
+	newCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	_ = newCtx
+
+	return fetch(ctx) // want "child work uses the pre-span context ctx, not newCtx returned by Start"
+}
+
+func _(ctx context.Context) {
+	var spanCtx, span = otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	_ = spanCtx
+
+	go func() {
+		_ = fetch(ctx) // want "child work uses the pre-span context ctx, not spanCtx returned by Start"
+	}()
+	_ = fetch(ctx)
+}
+
+// correct
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return fetch(ctx)
+}
+
+func _(ctx context.Context) error {
+	newCtx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	_ = fetch(ctx) // the returned context is used for other work
+	return fetch(newCtx)
+}
+
+func _(ctx context.Context) error {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return fetch(ctx)
+}
+
+func _(ctx context.Context) error {
+	_ = fetch(ctx)
+
+	newCtx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	_ = newCtx
+
+	return fetch(ctx)
+}
@@ -0,0 +1,81 @@
+package useafterend
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// incorrect
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+	span.SetAttributes(attribute.String("foo", "bar")) // want "span.SetAttributes after End has no effect"
+	span.AddEvent("foo")                               // want "span.AddEvent after End has no effect"
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error()) // want "span.SetStatus after End has no effect"
+		span.RecordError(err)                    // want "span.RecordError after End has no effect"
+		return err
+	}
+
+	return nil
+}
+
+func _(ok bool) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	if ok {
+		span.End()
+	}
+	span.AddEvent("foo") // want "span.AddEvent after End has no effect"
+	span.End()
+}
+
+// correct
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	span.AddEvent("foo")
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.AddEvent("foo")
+	span.End()
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	span.End()
+
+	_, span = otel.Tracer("foo").Start(context.Background(), "bar")
+	span.AddEvent("foo")
+	span.End()
+}
+
+func _() {
+	for i := 0; i < 3; i++ {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		span.AddEvent("foo")
+		span.End()
+	}
+}
+
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		span.AddEvent("foo")
+	}()
+	span.End()
+}
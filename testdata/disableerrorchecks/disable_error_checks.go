@@ -23,6 +23,20 @@ func _() error {
 	return nil
 }
 
+// Read-only span methods do not satisfy the checks, or match the ignored signatures.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	if span.IsRecording() && span.SpanContext().IsValid() {
+		err := errors.New("foo")
+		_ = span.TracerProvider().Tracer("foo")
+		return err // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+
+	return nil
+}
+
 // correct
 
 func _() error {
@@ -55,6 +69,18 @@ func _() error {
 	return err
 }
 
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	err := errors.New("foo")
+	_ = span.SpanContext().TraceID()
+	_ = span.TracerProvider()
+	err = telemetry.Record(span, err)
+	_ = span.IsRecording()
+	return err
+}
+
 func recordErr(span trace.Span, err error) {}
 
 // https://github.com/jjti/go-spancheck/issues/24
@@ -0,0 +1,41 @@
+package enableall
+
+import (
+	"context"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+)
+
+// Spans ended when their context is done, by context.AfterFunc.
+
+// correct
+
+func _(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "foo")
+	context.AfterFunc(ctx, span.End)
+}
+
+func _(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ctx, span := trace.StartSpan(ctx, "foo")
+	stop := context.AfterFunc(ctx, span.End)
+	defer stop()
+
+	span.AddAttributes(trace.StringAttribute("bar", "baz"))
+}
+
+func _(ctx context.Context) {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	context.AfterFunc(ctx, func() { span.End() })
+}
+
+// incorrect
+
+func _(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "foo") // want "span.End is not called on all paths, possible memory leak"
+	context.AfterFunc(ctx, func() {})
+	_ = span
+} // want "return can be reached without calling span.End"
@@ -0,0 +1,52 @@
+package enableall
+
+import (
+	"errors"
+
+	"github.com/jjti/go-spancheck/testdata/enableall/util"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Spans started by a call wrapped in a conversion, type assertion or parentheses.
+
+// incorrect
+
+func _() {
+	span := oteltrace.Span(util.TestStartTrace()) // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _() {
+	span := any(util.TestStartTrace()).(oteltrace.Span) // want "span.End is not called on all paths, possible memory leak"
+	_ = span
+} // want "return can be reached without calling span.End"
+
+func _() error {
+	span := (util.TestStartTrace()) // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return errors.New("foo") // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+// correct
+
+func _() {
+	span := oteltrace.Span(util.TestStartTrace())
+	defer span.End()
+}
+
+func _() {
+	span := any(util.TestStartTrace()).(oteltrace.Span)
+	defer span.End()
+}
+
+func _() error {
+	span := (util.TestStartTrace())
+	defer span.End()
+
+	err := errors.New("foo")
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+	return err
+}
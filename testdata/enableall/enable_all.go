@@ -9,6 +9,7 @@ import (
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type testError struct{}
@@ -161,8 +162,57 @@ func _() {
 	fmt.Print(span)
 } // want "return can be reached without calling span.End"
 
+func _() {
+	var span = util.TestStartTrace() // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+} // want "return can be reached without calling span.End"
+
 // correct
 
+func _() error {
+	ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	_ = span.TracerProvider()
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		_ = span.SpanContext()
+		span.SetStatus(codes.Error, err.Error())
+		_ = span.IsRecording()
+		span.RecordError(err)
+		_ = oteltrace.SpanFromContext(ctx).SpanContext()
+		return err
+	}
+
+	return nil
+}
+
+func _(code codes.Code) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(code, err.Error()) // a status code that is not a constant satisfies the check
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer (span).End()
+
+	if true {
+		err := errors.New("foo")
+		(span).SetStatus(codes.Error, err.Error())
+		(span).RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
 func _() error {
 	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
 	defer span.End()
@@ -287,3 +337,273 @@ func _() (err error) {
 
 	return errors.New("test")
 }
+
+// SetStatus and RecordError inside an IsRecording guard satisfy the checks.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		if span.IsRecording() {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		if !span.IsRecording() {
+			return err
+		}
+
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// A span that is not recording must still be ended.
+func _() {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	if !span.IsRecording() {
+		return // want "return can be reached without calling span.End"
+	}
+
+	span.End()
+}
+
+// Single-name span declarations.
+func _() {
+	var span = util.TestStartTrace()
+	defer span.End()
+}
+
+func _() {
+	var span oteltrace.Span = util.TestStartTrace()
+	defer span.End()
+}
+
+// Tracers that return only a span.
+func _() error {
+	span := util.TestStartTrace() // want "span.End is not called on all paths, possible memory leak" "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	fmt.Print(span)
+
+	return errors.New("foo") // want "return can be reached without calling span.End" "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+func _() {
+	util.TestStartTrace() // want "span is unassigned, probable memory leak"
+}
+
+func _() error {
+	var span oteltrace.Span
+	span = util.TestStartTrace()
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() {
+	ctx, span1 := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span1.End()
+
+	span2 := util.TestStartTrace() // want "span2.End is not called on all paths, possible memory leak"
+	fmt.Print(ctx, span2)
+} // want "return can be reached without calling span2.End"
+
+// Checks disabled by a directive.
+//
+//spancheck:disable
+func _() error {
+	otel.Tracer("foo").Start(context.Background(), "bar")
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	fmt.Print(span)
+
+	return errors.New("foo")
+}
+
+//spancheck:disable
+func _() error {
+	f := func() {
+		_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		fmt.Print(span)
+	}
+	f()
+
+	return nil
+}
+
+//spancheck:disable set-status,record-error
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	fmt.Print(span)
+
+	return errors.New("foo") // want "return can be reached without calling span.End"
+}
+
+//spancheck:disable end
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	fmt.Print(span)
+
+	return errors.New("foo") // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+// Reading the span context does not satisfy, or interfere with, the checks.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak" "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	sc := span.SpanContext()
+	fmt.Print(sc.TraceID())
+
+	return errors.New("foo") // want "return can be reached without calling span.End" "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+
+	if sc := span.SpanContext(); !sc.IsValid() {
+		err := errors.New("foo")
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Spans read from a context are owned by whoever started them.
+func _(ctx context.Context) error {
+	span := oteltrace.SpanFromContext(ctx)
+	fmt.Print(span.SpanContext().TraceID())
+
+	return errors.New("foo")
+}
+
+func _(ctx context.Context) string {
+	return oteltrace.SpanContextFromContext(ctx).TraceID().String()
+}
+
+// Calls through an alias of the span count as calls on the span.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	var s oteltrace.Span
+	s = span
+	defer s.End()
+
+	if err := errors.New("foo"); err != nil {
+		s.SetStatus(codes.Error, err.Error())
+		s.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	s := span
+	defer s.End()
+
+	return errors.New("foo") // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+// Calls in a deferred function literal satisfy all checks.
+func _() error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	err := errors.New("foo")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "")
+		}
+		span.End()
+	}()
+
+	return err
+}
+
+func _(fail bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	var err error
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if fail {
+		err = errors.New("foo")
+		return err
+	}
+
+	return nil
+}
+
+func _(fail bool) error {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar") // want "span.End is not called on all paths, possible memory leak" "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	if fail {
+		return errors.New("foo") // want "return can be reached without calling span.End" "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+
+	defer func() {
+		span.RecordError(nil)
+		span.SetStatus(codes.Error, "")
+		span.End()
+	}()
+
+	return nil
+}
+
+// A deferred closure can annotate the span from a named error result.
+func _(fail bool) (err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if fail {
+		return errors.New("foo")
+	}
+
+	return nil
+}
+
+func _(fail bool) (n int, err error) {
+	_, span := otel.Tracer("foo").Start(context.Background(), "bar")
+	defer span.End()
+	defer func() {
+		if err == nil {
+			return
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}()
+
+	if fail {
+		return 0, errors.New("foo")
+	}
+
+	return 1, nil
+}
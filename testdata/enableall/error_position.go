@@ -0,0 +1,88 @@
+package enableall
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Errors returned in any position of the results, not just the last.
+
+type result struct{}
+
+func parseFirst() (error, result) { return nil, result{} }
+
+func parseMiddle() (int, error, string) { return 0, nil, "" }
+
+// incorrect
+
+func _(ctx context.Context) (error, int) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return errors.New("foo"), 0 // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+func _(ctx context.Context) (error, result) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return parseFirst() // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+func _(ctx context.Context) (int, error, string) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return 0, errors.New("foo"), "" // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+func _(ctx context.Context) (int, error, string) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	return parseMiddle() // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+}
+
+// correct
+
+func _(ctx context.Context) (error, int) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return nil, 0
+}
+
+func _(ctx context.Context) (int, error, string) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	return 0, nil, ""
+}
+
+func _(ctx context.Context) (error, int) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err, 0
+	}
+	return nil, 0
+}
+
+func _(ctx context.Context) (int, error, string) {
+	_, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+
+	n, err, s := parseMiddle()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return 0, err, ""
+	}
+	return n, nil, s
+}
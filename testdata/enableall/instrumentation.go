@@ -0,0 +1,46 @@
+package enableall
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Spans started by instrumentation, eg otelhttp.NewHandler or otelgrpc, are read from the
+// context and are not the handler's to end.
+func _(w http.ResponseWriter, r *http.Request) {
+	span := oteltrace.SpanFromContext(r.Context())
+	span.SetAttributes(attribute.String("foo", "bar"))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func _(r *http.Request) error {
+	span := oteltrace.SpanFromContext(r.Context())
+	if err := errors.New("foo"); err != nil {
+		return err
+	}
+
+	span.AddEvent("foo")
+	return nil
+}
+
+func _(r *http.Request) error {
+	span := oteltrace.SpanFromContext(r.Context())
+	err := errors.New("foo")
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	return err
+}
+
+// Child spans started in an instrumented handler are still checked.
+func _(w http.ResponseWriter, r *http.Request) {
+	_, span := oteltrace.SpanFromContext(r.Context()).TracerProvider().Tracer("foo").Start(r.Context(), "bar") // want "span.End is not called on all paths, possible memory leak"
+	span.AddEvent("foo")
+
+	w.WriteHeader(http.StatusOK)
+} // want "return can be reached without calling span.End"
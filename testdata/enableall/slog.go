@@ -0,0 +1,60 @@
+package enableall
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Spans combined with log/slog's context integration. Logging the error is not recording
+// it on the span.
+
+// incorrect
+
+func _(ctx context.Context, logger *slog.Logger) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.SetStatus is not called on all paths" "span.RecordError is not called on all paths"
+	defer span.End()
+
+	if err := errors.New("foo"); err != nil {
+		logger.ErrorContext(ctx, "failed", "err", err)
+		slog.ErrorContext(ctx, "failed", "err", err)
+		return err // want "return can be reached without calling span.SetStatus" "return can be reached without calling span.RecordError"
+	}
+
+	return nil
+}
+
+func _(ctx context.Context) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar") // want "span.End is not called on all paths, possible memory leak"
+	slog.InfoContext(ctx, "started")
+
+	if err := errors.New("foo"); err != nil {
+		slog.ErrorContext(ctx, "failed", "err", err)
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err // want "return can be reached without calling span.End"
+	}
+
+	span.End()
+	return nil
+}
+
+// correct
+
+func _(ctx context.Context, logger *slog.Logger) error {
+	ctx, span := otel.Tracer("foo").Start(ctx, "bar")
+	defer span.End()
+	logger.InfoContext(ctx, "started")
+
+	if err := errors.New("foo"); err != nil {
+		logger.ErrorContext(ctx, "failed", "err", err)
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
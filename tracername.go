@@ -0,0 +1,68 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultTracerNameTemplate is the tracer name expected by the tracer-name check, by default
+// the package's import path, as recommended by OpenTelemetry.
+const defaultTracerNameTemplate = "{{.Path}}"
+
+// tracerNameData is the data the TracerNameTemplate is executed with.
+type tracerNameData struct {
+	// Path is the package's import path, eg github.com/org/repo/pkg.
+	Path string
+
+	// Name is the package's name, eg pkg.
+	Name string
+}
+
+// reportTracerNames reports tracers created with a constant name, eg otel.Tracer("foo"),
+// that differs from the name expected for the package.
+func reportTracerNames(pass *analysis.Pass, config *Config) {
+	var expected strings.Builder
+	if err := config.tracerName.Execute(&expected, tracerNameData{Path: pass.Pkg.Path(), Name: pass.Pkg.Name()}); err != nil {
+		config.logger().Printf("[WARN] failed to execute tracer name template %q, skipping tracer-name check: %v\n", config.TracerNameTemplate, err)
+		return
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 || !isTracerCall(pass.TypesInfo, call) {
+				return true
+			}
+
+			tv, ok := pass.TypesInfo.Types[call.Args[0]]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+				return true // not a constant name
+			}
+			if name := constant.StringVal(tv.Value); name != expected.String() {
+				config.report(pass, TracerNameCheck, call.Args[0], "tracer name %q does not match %q", name, expected.String())
+			}
+			return true
+		})
+	}
+}
+
+// isTracerCall reports whether call creates a tracer, with otel.Tracer(name) or
+// TracerProvider.Tracer(name).
+func isTracerCall(info *types.Info, call *ast.CallExpr) bool {
+	fn, ok := calleeObject(info, call).(*types.Func)
+	if !ok || fn.Name() != "Tracer" || fn.Pkg() == nil {
+		return false
+	}
+
+	switch fn.Pkg().Path() {
+	case "go.opentelemetry.io/otel":
+		return true
+	case "go.opentelemetry.io/otel/trace":
+		return strings.HasPrefix(fn.FullName(), "(go.opentelemetry.io/otel/trace.TracerProvider)")
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportUnusedContext reports calls passed the context a span is started from, after the span
+// is started, when the context returned by the start is bound to another variable that is never
+// used, eg `newCtx, span := tracer.Start(ctx, "op")` followed by `fetch(ctx)`. Spans started
+// from the old context are not children of the span.
+func reportUnusedContext(pass *analysis.Pass, config *Config, node ast.Node, sv spanVar) {
+	newCtx := getContextVar(pass.TypesInfo, sv.stmt)
+	oldCtx := getStartContextArg(pass.TypesInfo, sv.stmt)
+	if newCtx == nil || oldCtx == nil || newCtx == oldCtx || isContextUsed(pass.TypesInfo, node, newCtx) {
+		return
+	}
+
+	// Only the first use is reported.
+	reported := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if reported || !ok || call.Pos() < sv.stmt.End() {
+			return !reported
+		}
+		for _, arg := range call.Args {
+			if id, ok := ast.Unparen(arg).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == oldCtx {
+				config.report(pass, UnusedContextCheck, arg, "child work uses the pre-span context %s, not %s returned by Start", oldCtx.Name(), newCtx.Name())
+				reported = true
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// getStartContextArg returns the context variable passed to the call starting a span in stmt,
+// eg ctx in `newCtx, span := tracer.Start(ctx, "op")`.
+func getStartContextArg(info *types.Info, stmt ast.Node) *types.Var {
+	var rhs ast.Expr
+	switch stmt := stmt.(type) {
+	case *ast.ValueSpec:
+		if len(stmt.Values) == 1 {
+			rhs = stmt.Values[0]
+		}
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			rhs = stmt.Rhs[0]
+		}
+	}
+
+	call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for _, arg := range call.Args {
+		if id, ok := ast.Unparen(arg).(*ast.Ident); ok && isContext(info.TypeOf(id)) {
+			v, _ := info.Uses[id].(*types.Var)
+			return v
+		}
+	}
+	return nil
+}
+
+// isContextUsed reports whether v is used in node other than by assigning it to the blank
+// identifier, eg `_ = newCtx`.
+func isContextUsed(info *types.Info, node ast.Node, v *types.Var) bool {
+	used := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && isBlankAssign(assign) {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == v {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// isBlankAssign reports whether assign only assigns to the blank identifier.
+func isBlankAssign(assign *ast.AssignStmt) bool {
+	for _, lhs := range assign.Lhs {
+		if id, ok := lhs.(*ast.Ident); !ok || id.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package spancheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// spanOperations are the span methods that have no effect after span.End() is called.
+var spanOperations = map[string]struct{}{
+	"AddEvent":      {},
+	"AddLink":       {},
+	"RecordError":   {},
+	"SetAttributes": {},
+	"SetName":       {},
+	"SetStatus":     {},
+}
+
+// reportUseAfterEnd reports span operations that can be reached after span.End() is called
+// on the same span, where they have no effect. Operations in deferred and nested functions
+// are not checked, since they may run at a different time.
+func reportUseAfterEnd(pass *analysis.Pass, config *Config, g *cfg.CFG, spanVars map[*ast.Ident]spanVar) {
+	if g == nil {
+		return
+	}
+
+	// Assigning a new span to the variable starts over.
+	defs := make(map[*types.Var]map[ast.Node]bool)
+	for _, sv := range spanVars {
+		if defs[sv.vr] == nil {
+			defs[sv.vr] = make(map[ast.Node]bool)
+		}
+		defs[sv.vr][sv.stmt] = true
+	}
+
+	reported := make(map[ast.Node]bool)
+	for v := range defs {
+		for _, b := range g.Blocks {
+			for i, n := range b.Nodes {
+				if !isEndCall(pass.TypesInfo, n, v) {
+					continue
+				}
+
+				for _, op := range getOperationsAfter(pass.TypesInfo, b, b.Nodes[i+1:], v, defs[v]) {
+					if !reported[op] {
+						reported[op] = true
+						config.report(pass, UseAfterEndCheck, op, "%s.%s after End has no effect", v.Name(), op.Sel.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// getOperationsAfter returns the span operations on v that can be reached from rest, the
+// statements of b after a call to v.End(). The search stops at statements in defs, which
+// assign a new span to v.
+func getOperationsAfter(info *types.Info, b *cfg.Block, rest []ast.Node, v *types.Var, defs map[ast.Node]bool) []*ast.SelectorExpr {
+	var ops []*ast.SelectorExpr
+	seen := make(map[*cfg.Block]bool)
+
+	var search func(b *cfg.Block, nodes []ast.Node)
+	search = func(b *cfg.Block, nodes []ast.Node) {
+		for _, n := range nodes {
+			if defs[n] {
+				return
+			}
+
+			ast.Inspect(n, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit, *ast.DeferStmt:
+					return false
+				case *ast.SelectorExpr:
+					if _, ok := spanOperations[n.Sel.Name]; !ok {
+						return true
+					}
+					if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && info.Uses[id] == v {
+						ops = append(ops, n)
+					}
+				}
+				return true
+			})
+		}
+
+		for _, succ := range b.Succs {
+			if !seen[succ] {
+				seen[succ] = true
+				search(succ, succ.Nodes)
+			}
+		}
+	}
+	search(b, rest)
+
+	return ops
+}